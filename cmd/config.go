@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/cqfn/refrax/internal/client"
+	"github.com/cqfn/refrax/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func newConfigCmd(params *client.Params) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "config",
+		Short: "Work with the project configuration (" + config.File + ")",
+	}
+	show := &cobra.Command{
+		Use:   "show [path]",
+		Short: "Print the effective configuration of the project in the given directory (defaults to current)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			path := "."
+			if len(args) > 0 {
+				path = args[0]
+			}
+			if err := configure(c, params, path); err != nil {
+				return err
+			}
+			_, err := fmt.Fprint(params.Log, config.Effective(params))
+			return err
+		},
+	}
+	refactoringFlags(show, params)
+	command.AddCommand(show)
+	return command
+}
+
+// configure loads the configuration file of the project and applies it to the params.
+// The flags set in the command line take precedence over the file.
+func configure(c *cobra.Command, params *client.Params, root string) error {
+	cfg, err := config.Load(root)
+	if err != nil {
+		return err
+	}
+	cfg.Apply(params, c.Flags().Changed)
+	return nil
+}
@@ -8,28 +8,52 @@ import (
 
 func newRefactorCmd(params *client.Params) *cobra.Command {
 	var output string
-	var maxSize int
-	var checks []string
 	command := &cobra.Command{
 		Use:     "refactor [path]",
 		Short:   "Refactor code in the given directory (defaults to current)",
 		Args:    cobra.MaximumNArgs(1),
 		Aliases: []string{"r"},
-		RunE: func(_ *cobra.Command, args []string) error {
+		RunE: func(c *cobra.Command, args []string) error {
 			path := "."
 			if len(args) > 0 {
 				path = args[0]
 			}
 			params.Input = path
 			params.Output = output
-			params.MaxSize = maxSize
-			params.Checks = checks
+			if params.Patch != "" {
+				params.DryRun = true
+			}
+			if err := configure(c, params, path); err != nil {
+				return err
+			}
 			_, err := client.Refactor(params)
 			return err
 		},
 	}
 	command.Flags().StringVarP(&output, "output", "o", "", "Output path for the refactored code")
-	command.Flags().IntVar(&maxSize, "max-size", 200, "Maximum number of changes allowed in a single refactoring cycle")
-	command.Flags().StringSliceVar(&checks, "check", make([]string, 0), "Check commands to run after refactoring")
+	refactoringFlags(command, params)
+	command.Flags().StringVar(&params.Since, "since", "", "Refactor only the classes changed since the common ancestor with this git ref, e.g. 'origin/main'")
+	command.Flags().BoolVar(&params.Staged, "staged", false, "Refactor only the classes staged in git")
+	command.Flags().BoolVar(&params.DryRun, "dry-run", false, "Keep the changes in memory and leave the project intact, the reviewer checks a temporary copy")
+	command.Flags().StringVar(&params.Patch, "patch", "", "Path of the unified diff with the changes of a dry run, implies --dry-run (printed to the output by default)")
+	command.Flags().BoolVar(&params.Commit, "commit", false, "Create a git commit for every successful refactoring round")
+	command.Flags().StringVar(&params.Branch, "branch", "", "Create this git branch for the commits of refactoring rounds (implies --commit)")
+	command.Flags().StringVar(&params.CriticURL, "critic-url", "", "URL of a running critic agent to use instead of a local one")
+	command.Flags().StringVar(&params.FixerURL, "fixer-url", "", "URL of a running fixer agent to use instead of a local one")
+	command.Flags().StringVar(&params.ReviewerURL, "reviewer-url", "", "URL of a running reviewer agent to use instead of a local one")
+	command.Flags().StringVar(&params.FacilitatorURL, "facilitator-url", "", "URL of a running facilitator agent to use instead of local agents")
 	return command
 }
+
+// refactoringFlags adds the flags of a refactoring that the project configuration can set as well.
+func refactoringFlags(command *cobra.Command, params *client.Params) {
+	command.Flags().IntVar(&params.MaxSize, "max-size", 200, "Maximum number of changes allowed in a single refactoring cycle")
+	command.Flags().StringVar(&params.Prioritizer, "prioritizer", "local", "How to choose the suggestions to apply in a round: 'local' clusters them, 'llm' asks the AI")
+	command.Flags().StringVar(&params.Weights, "weights", "", "Weights of the local prioritizer, e.g. 'severity=2,frequency=1,spread=1'")
+	command.Flags().IntVar(&params.TokenLimit, "token-limit", 6_000, "Number of tokens of a class the agents take at once, larger classes are split into chunks of members; fit it to the context window of the models")
+	command.Flags().StringSliceVar(&params.Examples, "example", make([]string, 0), "Glob patterns of the classes whose style the fixer follows, e.g. 'src/main/**/Order.java', or 'auto' to pick the classes that pass the checks")
+	command.Flags().StringVar(&params.FixMode, "fix-mode", "full", "How the fixer changes a class: 'full' rewrites the whole file, 'edits' applies search/replace blocks")
+	command.Flags().StringSliceVar(&params.Checks, "check", make([]string, 0), "Check commands to run after refactoring")
+	command.Flags().StringSliceVar(&params.Include, "include", make([]string, 0), "Glob patterns of the classes to refactor, e.g. 'src/main/**' (all classes by default)")
+	command.Flags().StringSliceVar(&params.Exclude, "exclude", make([]string, 0), "Glob patterns of the classes to skip, e.g. 'src/test/**'")
+}
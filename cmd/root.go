@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"github.com/cqfn/refrax/internal/client"
 	"github.com/cqfn/refrax/internal/util"
@@ -16,10 +18,6 @@ func Execute() error {
 
 // NewRootCmd creates and returns the root command for Refrax.
 // Command line interface for Refrax.
-// @todo #2:45min Add new parameter `--tools` for constructing needed tool for the critic.
-// Currently, we create Aibolit, but it would be great to have such option. Examples of such tools
-// are: `aibolit`, `none`, etc. We should be able to pass multiple tools, for instance:
-// `--tools=aibolit,qulice`.
 func NewRootCmd(out, _ io.Writer) *cobra.Command {
 	var params client.Params
 	root := &cobra.Command{
@@ -28,7 +26,7 @@ func NewRootCmd(out, _ io.Writer) *cobra.Command {
 		Long:             "Refrax is an AI-powered refactoring agent for Java code. It communicates using the A2A protocol",
 		PersistentPreRun: func(_ *cobra.Command, _ []string) { params.Log = out },
 	}
-	root.PersistentFlags().StringVarP(&params.Provider, "ai", "a", "none", "AI provider to use (openai, deepseek, none)")
+	root.PersistentFlags().StringVarP(&params.Provider, "ai", "a", "none", "AI provider to use (openai, deepseek, openai-compatible, anthropic, ollama, none)")
 	root.PersistentFlags().StringVarP(&params.Token, "token", "t", "", "Token for the AI provider (if required)")
 	root.PersistentFlags().StringVar(&params.Playbook, "playbook", "", "Path to a user-defined YAML playbook for AI integration")
 	root.PersistentFlags().BoolVar(&params.MockProject, "mock-project", false, "Use mock project")
@@ -38,10 +36,34 @@ func NewRootCmd(out, _ io.Writer) *cobra.Command {
 	root.PersistentFlags().StringVar(&params.Soutput, "stats-output", "stats", "Output path for statistics")
 	root.PersistentFlags().BoolVar(&params.Colorless, "no-colors", false, "Disable colored output")
 	root.PersistentFlags().StringVarP(&params.Model, "model", "m", "", "Model to use (if supported by the AI provider)")
+	root.PersistentFlags().StringVar(&params.BaseURL, "base-url", "", "Base URL of the AI provider API, required for openai-compatible (e.g. http://localhost:8000/v1)")
+	root.PersistentFlags().Float64Var(&params.Temperature, "temperature", -1, "Sampling temperature of the AI model (negative means the provider default)")
+	root.PersistentFlags().IntVar(&params.MaxTokens, "max-tokens", 0, "Maximum number of tokens in an AI answer (if supported by the AI provider)")
 	root.PersistentFlags().IntVar(&params.Attempts, "attempts", 3, "How many attempts the AI has to make a valid refactoring")
+	root.PersistentFlags().IntVar(&params.Retries, "retries", 3, "How many times a failed AI request is retried (rate limits, timeouts, server errors)")
+	root.PersistentFlags().IntVar(&params.MaxConcurrency, "max-concurrency", 0, "Maximum number of concurrent AI requests of all agents (0 means no limit)")
+	root.PersistentFlags().IntVar(&params.RPM, "rpm", 0, "Maximum number of AI requests per minute of all agents (0 means no limit)")
+	root.PersistentFlags().IntVar(&params.TPM, "tpm", 0, "Maximum number of AI tokens per minute of all agents, questions and answers (0 means no limit)")
+	root.PersistentFlags().DurationVar(&params.RetryDelay, "retry-delay", time.Second, "Initial delay between retries of a failed AI request, doubled after every retry")
+	root.PersistentFlags().StringSliceVar(&params.Tools, "tools", make([]string, 0), "Tools that help the critic to find imperfections (none)")
+	roles := []struct {
+		name string
+		role *client.Role
+	}{
+		{"critic", &params.Critic},
+		{"fixer", &params.Fixer},
+		{"reviewer", &params.Reviewer},
+		{"facilitator", &params.Facilitator},
+	}
+	for _, r := range roles {
+		root.PersistentFlags().StringVar(&r.role.Provider, r.name+"-ai", "", fmt.Sprintf("AI provider of the %s (defaults to --ai)", r.name))
+		root.PersistentFlags().StringVar(&r.role.Model, r.name+"-model", "", fmt.Sprintf("Model of the %s (defaults to --model)", r.name))
+		root.PersistentFlags().StringVar(&r.role.Token, r.name+"-token", "", fmt.Sprintf("Token for the AI provider of the %s (defaults to --token)", r.name))
+	}
 	root.AddCommand(
 		newRefactorCmd(&params),
-		newStartCmd(),
+		newStartCmd(&params),
+		newConfigCmd(&params),
 	)
 	root.Version = util.Version()
 	root.SetVersionTemplate("refrax {{.Version}}\n")
@@ -1,18 +1,36 @@
 package cmd
 
 import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/cqfn/refrax/internal/client"
 	"github.com/spf13/cobra"
 )
 
-func newStartCmd() *cobra.Command {
+func newStartCmd(params *client.Params) *cobra.Command {
+	var checks []string
 	command := &cobra.Command{
-		Use:     "start [agent]",
-		Short:   "Start a particular agent (fixer, critic, facilitator)",
-		Args:    cobra.MaximumNArgs(1),
-		Aliases: []string{"st"},
-		RunE: func(_ *cobra.Command, _ []string) error {
-			panic("Start command is not implemented yet")
+		Use:       "start [agent]",
+		Short:     "Start a particular agent (critic, fixer, reviewer, facilitator) as a standalone A2A server",
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: []string{"critic", "fixer", "reviewer", "facilitator"},
+		Aliases:   []string{"st"},
+		RunE: func(c *cobra.Command, args []string) error {
+			params.Checks = checks
+			if err := configure(c, params, "."); err != nil {
+				return err
+			}
+			ctx, stop := signal.NotifyContext(c.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return client.Start(ctx, params, args[0])
 		},
 	}
+	command.Flags().IntVar(&params.Port, "port", 8080, "Port to listen on")
+	command.Flags().StringSliceVar(&checks, "check", make([]string, 0), "Check commands to run by the reviewer")
+	command.Flags().StringVar(&params.CriticURL, "critic-url", "", "URL of a running critic agent (facilitator only)")
+	command.Flags().StringVar(&params.FixerURL, "fixer-url", "", "URL of a running fixer agent (facilitator only)")
+	command.Flags().StringVar(&params.ReviewerURL, "reviewer-url", "", "URL of a running reviewer agent (facilitator only)")
 	return command
 }
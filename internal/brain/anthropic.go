@@ -0,0 +1,146 @@
+package brain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cqfn/refrax/internal/log"
+)
+
+// anthropicVersion is the version of the Messages API the client speaks.
+const anthropicVersion = "2023-06-01"
+
+// anthropicModel is the model used when no model is configured.
+const anthropicModel = "claude-sonnet-4-5"
+
+// anthropicMaxTokens is the maximum length of an answer when no limit is configured.
+// Fixed classes are returned in full, so the limit must fit a whole Java file.
+const anthropicMaxTokens = 8192
+
+// anthropicBrain represents a client for interacting with the Anthropic Messages API.
+type anthropicBrain struct {
+	token     string
+	url       string
+	model     string
+	system    string
+	maxTokens int
+	client    *http.Client
+}
+
+type anthropicReq struct {
+	Model     string         `json:"model"`
+	MaxTokens int            `json:"max_tokens"`
+	System    string         `json:"system,omitempty"`
+	Messages  []anthropicMsg `json:"messages"`
+}
+
+type anthropicMsg struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicResp struct {
+	Content    []anthropicBlock `json:"content"`
+	StopReason string           `json:"stop_reason"`
+}
+
+type anthropicBlock struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type anthropicErr struct {
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// NewAnthropic creates a client for the Anthropic Messages API.
+// Empty model and non-positive maxTokens are replaced with the defaults.
+func NewAnthropic(apiKey, model, system string, maxTokens int) Brain {
+	if model == "" {
+		model = anthropicModel
+	}
+	if maxTokens <= 0 {
+		maxTokens = anthropicMaxTokens
+	}
+	return &anthropicBrain{
+		token:     apiKey,
+		url:       "https://api.anthropic.com/v1/messages",
+		model:     model,
+		system:    system,
+		maxTokens: maxTokens,
+		client:    &http.Client{Timeout: timeout},
+	}
+}
+
+// Ask sends a question to the Anthropic API and returns the text of the answer.
+func (a *anthropicBrain) Ask(ctx context.Context, question string) (answer string, err error) {
+	log.Debug("Anthropic: asking question: %s", question)
+	body := anthropicReq{
+		Model:     a.model,
+		MaxTokens: a.maxTokens,
+		System:    a.system,
+		Messages: []anthropicMsg{
+			{Role: "user", Content: trimmed(question)},
+		},
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling request body: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, bytes.NewBuffer(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", a.token)
+	req.Header.Set("anthropic-version", anthropicVersion)
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error making request to anthropic api: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = fmt.Errorf("error closing response body: %w", cerr)
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return "", anthropicError(resp)
+	}
+	var parsed anthropicResp
+	if err = json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return "", fmt.Errorf("error decoding response: %w", err)
+	}
+	var text strings.Builder
+	for _, block := range parsed.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	if text.Len() == 0 {
+		return "", errors.New("no text in response")
+	}
+	if parsed.StopReason == "max_tokens" {
+		log.Warn("Anthropic: the answer was cut at %d tokens, consider increasing --max-tokens", a.maxTokens)
+	}
+	return text.String(), nil
+}
+
+// anthropicError reads the structured error of the Anthropic API, like
+// {"type": "error", "error": {"type": "rate_limit_error", "message": "..."}}.
+func anthropicError(resp *http.Response) *APIError {
+	res := apiError(resp)
+	var parsed anthropicErr
+	if err := json.Unmarshal([]byte(res.Body), &parsed); err == nil && parsed.Error.Message != "" {
+		res.Type = parsed.Error.Type
+		res.Body = parsed.Error.Message
+	}
+	return res
+}
@@ -0,0 +1,51 @@
+package brain
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnthropic_Ask_PositiveCase(t *testing.T) {
+	server := NewAnthropicServer(t, "claude-haiku-4-5", "test_api_key")
+	defer server.Close()
+	ai := NewAnthropic("test_api_key", "claude-haiku-4-5", "anthropic system prompt", 0)
+	ai.(*anthropicBrain).url = server.URL
+
+	answer, err := ai.Ask(context.Background(), "This is a test question")
+
+	require.NoError(t, err)
+	assert.Equal(t, "This is a test question", answer)
+}
+
+func TestAnthropic_Ask_UsesDefaultModel(t *testing.T) {
+	server := NewAnthropicServer(t, anthropicModel, "test_api_key")
+	defer server.Close()
+	ai := NewAnthropic("test_api_key", "", "anthropic system prompt", 0)
+	ai.(*anthropicBrain).url = server.URL
+
+	_, err := ai.Ask(context.Background(), "This is a test question")
+
+	require.NoError(t, err)
+}
+
+func TestAnthropic_Ask_ParsesStructuredError(t *testing.T) {
+	server := NewAnthropicErrorServer(t, http.StatusTooManyRequests, "rate_limit_error", "Number of requests has exceeded your rate limit")
+	defer server.Close()
+	ai := NewAnthropic("test_api_key", "", "anthropic system prompt", 0)
+	ai.(*anthropicBrain).url = server.URL
+
+	answer, err := ai.Ask(context.Background(), "This is a test question")
+
+	require.Empty(t, answer)
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusTooManyRequests, apiErr.Status)
+	assert.Equal(t, "rate_limit_error", apiErr.Type)
+	assert.Equal(t, "Number of requests has exceeded your rate limit", apiErr.Body)
+	assert.Equal(t, 3*time.Second, apiErr.RetryAfter)
+}
@@ -0,0 +1,59 @@
+package brain
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// APIError is an unsuccessful response of an LLM provider.
+type APIError struct {
+	// Status is the HTTP status code of the response.
+	Status int
+
+	// RetryAfter is the delay the provider asked for in the Retry-After header, zero if absent.
+	RetryAfter time.Duration
+
+	// Type is the kind of the error reported by the provider, empty if the provider doesn't report it.
+	Type string
+
+	// Body is the content of the response, it usually explains the error.
+	Body string
+}
+
+// Error returns the description of the error.
+func (e *APIError) Error() string {
+	if e.Type != "" {
+		return fmt.Sprintf("API error (status %d, %s): %s", e.Status, e.Type, e.Body)
+	}
+	return fmt.Sprintf("API error (status %d): %s", e.Status, e.Body)
+}
+
+// apiError reads an unsuccessful response into an APIError.
+func apiError(resp *http.Response) *APIError {
+	body, _ := io.ReadAll(resp.Body)
+	return &APIError{
+		Status:     resp.StatusCode,
+		RetryAfter: retryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		Body:       string(body),
+	}
+}
+
+// retryAfter parses the Retry-After header, that is either a number of seconds or an HTTP date.
+func retryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
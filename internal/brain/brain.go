@@ -1,36 +1,82 @@
 package brain
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"time"
+)
 
 // Brain represents an interface for asking questions and receiving answers.
 type Brain interface {
-	Ask(question string) (string, error)
+	// Ask sends the question to the model and returns the answer.
+	// The request is stopped as soon as the context is canceled.
+	Ask(ctx context.Context, question string) (string, error)
 }
 
+// timeout limits a single request to an LLM provider, the context may stop it earlier.
+const timeout = 5 * time.Minute
+
 const deepseek = "deepseek"
 
 const openai = "openai"
 
+const compat = "openai-compatible"
+
+const anthropic = "anthropic"
+
 const ollama = "ollama"
 
 const mock = "mock"
 
-// New creates a new instance of Brain based on the provided provider and optional playbook strings.
-func New(provider, token, model, system string, playbook ...string) (Brain, error) {
-	switch provider {
-	case deepseek:
-		return NewDeepSeek(token, system), nil
-	case openai:
-		return NewOpenAI(token, system), nil
-	case mock:
-		if len(playbook) == 0 {
-			return NewMock(), nil
+// Config holds the settings of a brain.
+type Config struct {
+	// Provider is the name of the AI provider: deepseek, openai, openai-compatible, anthropic, ollama or mock.
+	Provider string
+
+	// BaseURL is the address of the provider API, providers fall back to their default address if it is empty.
+	BaseURL string
+
+	// Token is the API key of the provider.
+	Token string
+
+	// Model is the model to use, providers fall back to their default model if it is empty.
+	Model string
+
+	// System is the system prompt of the agent.
+	System string
+
+	// Playbook is the path to a YAML playbook for the mock provider.
+	Playbook string
+
+	// MaxTokens limits the length of an answer, if the provider supports it.
+	MaxTokens int
+
+	// Temperature is the sampling temperature, providers use their default temperature if it is nil.
+	Temperature *float64
+}
+
+// New creates a new instance of Brain based on the provided configuration.
+func New(cfg Config) (Brain, error) {
+	switch cfg.Provider {
+	case deepseek, openai:
+		return NewCompatible(cfg), nil
+	case compat:
+		if cfg.BaseURL == "" {
+			return nil, fmt.Errorf("provider %s requires a base URL", compat)
 		}
-		return NewMock(playbook[0]), nil
+		return NewCompatible(cfg), nil
+	case anthropic:
+		return NewAnthropic(cfg.Token, cfg.Model, cfg.System, cfg.MaxTokens), nil
+	case mock:
+		return NewMock(cfg.Playbook), nil
 	case ollama:
-		return NewOllama("http://localhost:11434", model, token, system), nil
+		address := "http://localhost:11434"
+		if cfg.BaseURL != "" {
+			address = cfg.BaseURL
+		}
+		return NewOllama(address, cfg.Model, cfg.Token, cfg.System), nil
 	default:
-		return nil, fmt.Errorf("unknown provider: %s", provider)
+		return nil, fmt.Errorf("unknown provider: %s", cfg.Provider)
 	}
 }
 
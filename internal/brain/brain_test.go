@@ -12,25 +12,35 @@ const system = "system prompt"
 func TestNew_WithDeepSeekProvider_ReturnsDeepSeekBrain(t *testing.T) {
 	token := "valid_token"
 
-	result, err := New(deepseek, token, "gemma3", system)
+	result, err := New(Config{Provider: deepseek, Token: token, Model: "gemma3", System: system})
 
 	require.NoError(t, err, "Expected no error when creating DeepSeek brain")
-	_, ok := result.(*deepSeek)
+	_, ok := result.(*compatible)
 	require.True(t, ok, "Expected result to be of type DeepSeek")
 }
 
 func TestNew_WithOpenAIProvider_ReturnsOpenAIBrain(t *testing.T) {
 	token := "valid_openai_token"
 
-	result, err := New(openai, token, "llama", system)
+	result, err := New(Config{Provider: openai, Token: token, Model: "llama", System: system})
 
 	require.NoError(t, err, "Expected no error when creating OpenAI brain")
-	_, ok := result.(*openAI)
+	_, ok := result.(*compatible)
 	require.True(t, ok, "Expected result to be of type OpenAI")
 }
 
+func TestNew_WithAnthropicProvider_ReturnsAnthropicBrain(t *testing.T) {
+	result, err := New(Config{Provider: anthropic, Token: "valid_token", Model: "claude-haiku-4-5", System: system, MaxTokens: 1024})
+
+	require.NoError(t, err, "Expected no error when creating Anthropic brain")
+	ab, ok := result.(*anthropicBrain)
+	require.True(t, ok, "Expected result to be of type Anthropic")
+	assert.Equal(t, "claude-haiku-4-5", ab.model)
+	assert.Equal(t, 1024, ab.maxTokens)
+}
+
 func TestNew_MockProviderNoPlaybook_ReturnsMockInstance(t *testing.T) {
-	result, err := New(mock, "test-token", "qwen", system)
+	result, err := New(Config{Provider: mock, Token: "test-token", Model: "qwen", System: system})
 
 	require.NoError(t, err)
 	_, ok := result.(*mockBrain)
@@ -38,7 +48,7 @@ func TestNew_MockProviderNoPlaybook_ReturnsMockInstance(t *testing.T) {
 }
 
 func TestNew_UnknownProvider_ReturnsError(t *testing.T) {
-	b, err := New("unknown", "test-token", "deepseek", system)
+	b, err := New(Config{Provider: "unknown", Token: "test-token", Model: "deepseek", System: system})
 
 	require.Error(t, err)
 	assert.Nil(t, b)
@@ -0,0 +1,160 @@
+package brain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/cqfn/refrax/internal/log"
+)
+
+// compatible represents a client for any API compatible with the OpenAI Chat Completions API,
+// like OpenAI itself, DeepSeek, vLLM, LM Studio, llama.cpp server or OpenRouter.
+type compatible struct {
+	name        string
+	token       string
+	url         string
+	model       string
+	system      string
+	temperature *float64
+	maxTokens   int
+	client      *http.Client
+}
+
+// preset holds the defaults of a known provider.
+type preset struct {
+	base        string
+	model       string
+	temperature *float64
+}
+
+// presets lists the defaults of the providers that speak the Chat Completions API.
+var presets = map[string]preset{
+	openai:   {base: "https://api.openai.com/v1", model: "gpt-3.5-turbo"},
+	deepseek: {base: "https://api.deepseek.com", model: "deepseek-chat", temperature: new(float64)},
+}
+
+type chatReq struct {
+	Model       string    `json:"model"`
+	Messages    []chatMsg `json:"messages"`
+	Stream      bool      `json:"stream"`
+	Temperature *float64  `json:"temperature,omitempty"`
+	MaxTokens   int       `json:"max_tokens,omitempty"`
+}
+
+type chatResp struct {
+	Choices []chatChoice `json:"choices"`
+}
+
+type chatChoice struct {
+	Message chatMsg `json:"message"`
+}
+
+type chatMsg struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// NewOpenAI creates a new OpenAI instance
+func NewOpenAI(apiKey, system string) Brain {
+	return NewCompatible(Config{Provider: openai, Token: apiKey, System: system})
+}
+
+// NewDeepSeek creates a new DeepSeek instance with the provided API key and system prompt.
+func NewDeepSeek(apiKey, system string) Brain {
+	return NewCompatible(Config{Provider: deepseek, Token: apiKey, System: system})
+}
+
+// NewCompatible creates a client for an API compatible with the OpenAI Chat Completions API.
+// The base URL, the model and the temperature fall back to the defaults of the provider, if it is known.
+func NewCompatible(cfg Config) Brain {
+	defaults := presets[cfg.Provider]
+	base := defaults.base
+	if cfg.BaseURL != "" {
+		base = cfg.BaseURL
+	}
+	model := defaults.model
+	if cfg.Model != "" {
+		model = cfg.Model
+	}
+	temperature := defaults.temperature
+	if cfg.Temperature != nil {
+		temperature = cfg.Temperature
+	}
+	return &compatible{
+		name:        cfg.Provider,
+		token:       cfg.Token,
+		url:         completions(base),
+		model:       model,
+		system:      cfg.System,
+		temperature: temperature,
+		maxTokens:   cfg.MaxTokens,
+		client:      &http.Client{Timeout: timeout},
+	}
+}
+
+// Ask sends a question to the API and retrieves an answer.
+func (c *compatible) Ask(ctx context.Context, question string) (answer string, err error) {
+	content := strings.TrimSpace(trimmed(question))
+	log.Debug("%s: sending request with system prompt: '%s' and user prompt: '%s'", c.name, c.system, content)
+	body := chatReq{
+		Model: c.model,
+		Messages: []chatMsg{
+			{Role: "system", Content: c.system},
+			{Role: "user", Content: content},
+		},
+		Stream:      false,
+		Temperature: c.temperature,
+		MaxTokens:   c.maxTokens,
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling request body: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewBuffer(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error making request to %s api: %w", c.name, err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = fmt.Errorf("error closing response body: %w", cerr)
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return "", apiError(resp)
+	}
+	var parsed chatResp
+	if err = json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return "", fmt.Errorf("error decoding response: %w", err)
+	}
+	if len(parsed.Choices) == 0 {
+		return "", errors.New("no choices in response")
+	}
+	return parsed.Choices[0].Message.Content, nil
+}
+
+// completions returns the URL of the chat completions endpoint for the base URL of the API.
+// The query of the base URL is kept, since some gateways pass the API version in it.
+func completions(base string) string {
+	u, err := url.Parse(base)
+	if err != nil {
+		return base
+	}
+	if !strings.HasSuffix(u.Path, "/chat/completions") {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/chat/completions"
+	}
+	return u.String()
+}
@@ -0,0 +1,64 @@
+package brain
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompatible_Ask_SendsSettings(t *testing.T) {
+	var received chatReq
+	var path, query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		query = r.URL.RawQuery
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		_, _ = w.Write([]byte(`{"choices": [{"message": {"content": "ok"}}]}`))
+	}))
+	defer server.Close()
+	temperature := 0.7
+	ai, err := New(Config{
+		Provider:    compat,
+		BaseURL:     server.URL + "/openai/v1?api-version=2024-10-21",
+		Model:       "qwen2.5-coder",
+		System:      "compatible system prompt",
+		MaxTokens:   512,
+		Temperature: &temperature,
+	})
+	require.NoError(t, err)
+
+	answer, err := ai.Ask(context.Background(), "This is a test question")
+
+	require.NoError(t, err)
+	assert.Equal(t, "ok", answer)
+	assert.Equal(t, "/openai/v1/chat/completions", path)
+	assert.Equal(t, "api-version=2024-10-21", query)
+	assert.Equal(t, "qwen2.5-coder", received.Model)
+	assert.Equal(t, "compatible system prompt", received.Messages[0].Content)
+	assert.Equal(t, 512, received.MaxTokens)
+	require.NotNil(t, received.Temperature)
+	assert.InDelta(t, 0.7, *received.Temperature, 0.0001)
+}
+
+func TestCompatible_RequiresBaseURL(t *testing.T) {
+	_, err := New(Config{Provider: compat, Model: "qwen2.5-coder"})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "requires a base URL")
+}
+
+func TestDeepSeek_KeepsSystemPromptAndModel(t *testing.T) {
+	ai, err := New(Config{Provider: deepseek, Token: "token", Model: "deepseek-reasoner", System: "deepseek system prompt"})
+	require.NoError(t, err)
+
+	c := ai.(*compatible)
+
+	assert.Equal(t, "deepseek-reasoner", c.model)
+	assert.Equal(t, "deepseek system prompt", c.system)
+	assert.Equal(t, "https://api.deepseek.com/chat/completions", c.url)
+}
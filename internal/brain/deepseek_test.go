@@ -2,6 +2,7 @@
 package brain
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -12,9 +13,9 @@ func TestDeepSeek_Ask_PositiveCase(t *testing.T) {
 	defer server.Close()
 
 	deepseek := NewDeepSeek("test_api_key", "sysprompt")
-	deepseek.(*deepSeek).url = server.URL
+	deepseek.(*compatible).url = server.URL
 
-	answer, err := deepseek.Ask("This is a test question")
+	answer, err := deepseek.Ask(context.Background(), "This is a test question")
 	require.NoError(t, err)
 	require.Equal(t, "This is a test question", answer)
 }
@@ -24,10 +25,23 @@ func TestDeepSeek_Ask_NegativeCase(t *testing.T) {
 	defer server.Close()
 
 	deepseek := NewDeepSeek("test_api_key", "deepseek system prompt")
-	deepseek.(*deepSeek).url = server.URL
+	deepseek.(*compatible).url = server.URL
 
-	answer, err := deepseek.Ask("This is a test question")
+	answer, err := deepseek.Ask(context.Background(), "This is a test question")
 
 	require.Error(t, err)
 	require.Empty(t, answer)
 }
+
+func TestDeepSeek_Ask_StopsOnCanceledContext(t *testing.T) {
+	server := NewEchoServer(t, "deepseek-chat", "test_api_key")
+	defer server.Close()
+	deepseek := NewDeepSeek("test_api_key", "sysprompt")
+	deepseek.(*compatible).url = server.URL
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := deepseek.Ask(ctx, "This is a test question")
+
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,37 @@
+package brain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cqfn/refrax/internal/stats"
+)
+
+// LimitedBrain is a wrapper around a Brain that asks the shared Limiter
+// before every request, so the provider limits are not exceeded.
+type LimitedBrain struct {
+	origin  Brain
+	limiter *Limiter
+}
+
+// NewLimitedBrain creates a LimitedBrain wrapping the given Brain.
+func NewLimitedBrain(origin Brain, limiter *Limiter) Brain {
+	return &LimitedBrain{origin: origin, limiter: limiter}
+}
+
+// Ask waits until the request fits into the limits and sends the question to the underlying Brain.
+// The number of tokens of the question and the answer is estimated with stats.Tokens.
+func (b *LimitedBrain) Ask(ctx context.Context, question string) (string, error) {
+	tokens, err := stats.Tokens(question)
+	if err != nil {
+		return "", fmt.Errorf("failed to count tokens for question: %w", err)
+	}
+	release, err := b.limiter.Acquire(ctx, tokens)
+	if err != nil {
+		return "", err
+	}
+	answer, err := b.origin.Ask(ctx, question)
+	answered, _ := stats.Tokens(answer)
+	release(answered)
+	return answer, err
+}
@@ -0,0 +1,116 @@
+package brain
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// window is the period the request and token rates are measured in.
+const window = time.Minute
+
+// Limiter bounds the number of concurrent LLM requests, and the number of requests
+// and tokens per minute. A single Limiter is shared by all brains of a run.
+// A zero limit means there is no such limit.
+type Limiter struct {
+	slots chan struct{}
+	rpm   int
+	tpm   int
+	mu    sync.Mutex
+	used  []*usage
+	now   func() time.Time
+}
+
+// usage is a request made within the last minute.
+type usage struct {
+	at     time.Time
+	tokens int
+}
+
+// NewLimiter creates a limiter with the given maximum number of concurrent requests,
+// requests per minute and tokens per minute.
+func NewLimiter(concurrency, rpm, tpm int) *Limiter {
+	var slots chan struct{}
+	if concurrency > 0 {
+		slots = make(chan struct{}, concurrency)
+	}
+	return &Limiter{
+		slots: slots,
+		rpm:   rpm,
+		tpm:   tpm,
+		used:  make([]*usage, 0),
+		now:   time.Now,
+	}
+}
+
+// Acquire blocks until a request with the given estimated number of tokens fits into the limits,
+// or until the context is done. The returned function must be called when the request is finished,
+// with the number of tokens the answer took, to free the slot and account the tokens.
+func (l *Limiter) Acquire(ctx context.Context, tokens int) (func(answered int), error) {
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for a free request slot was canceled: %w", ctx.Err())
+		}
+	}
+	u, err := l.reserve(ctx, tokens)
+	if err != nil {
+		l.free()
+		return nil, err
+	}
+	return func(answered int) {
+		l.mu.Lock()
+		u.tokens += answered
+		l.mu.Unlock()
+		l.free()
+	}, nil
+}
+
+// reserve waits until the request fits into the per-minute limits and records it.
+func (l *Limiter) reserve(ctx context.Context, tokens int) (*usage, error) {
+	for {
+		u, wait := l.admit(tokens)
+		if u != nil {
+			return u, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for the rate limit was canceled: %w", ctx.Err())
+		case <-time.After(wait):
+		}
+	}
+}
+
+// admit records the request and returns its usage if it fits into the limits now, or returns how long
+// to wait otherwise. The check and the record happen at once, so concurrent requests can't both fit
+// into the last free place of the window.
+func (l *Limiter) admit(tokens int) (*usage, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	recent := l.used[:0]
+	spent := 0
+	for _, u := range l.used {
+		if now.Sub(u.at) < window {
+			recent = append(recent, u)
+			spent += u.tokens
+		}
+	}
+	l.used = recent
+	full := l.rpm > 0 && len(l.used) >= l.rpm
+	over := l.tpm > 0 && spent+tokens > l.tpm
+	if len(l.used) > 0 && (full || over) {
+		return nil, l.used[0].at.Add(window).Sub(now)
+	}
+	u := &usage{at: now, tokens: tokens}
+	l.used = append(l.used, u)
+	return u, 0
+}
+
+func (l *Limiter) free() {
+	if l.slots != nil {
+		<-l.slots
+	}
+}
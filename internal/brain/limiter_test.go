@@ -0,0 +1,113 @@
+package brain
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter_BoundsConcurrency(t *testing.T) {
+	limiter := NewLimiter(2, 0, 0)
+	var running, peak atomic.Int32
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := limiter.Acquire(context.Background(), 1)
+			if err != nil {
+				return
+			}
+			now := running.Add(1)
+			for {
+				p := peak.Load()
+				if now <= p || peak.CompareAndSwap(p, now) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+			release(1)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(2), peak.Load(), "At most two requests should run at once")
+}
+
+func TestLimiter_WaitsForRequestsPerMinute(t *testing.T) {
+	limiter := NewLimiter(0, 2, 0)
+	now := time.Date(2025, 10, 2, 12, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+	for range 2 {
+		release, err := limiter.Acquire(context.Background(), 10)
+		require.NoError(t, err)
+		release(0)
+	}
+
+	u, wait := limiter.admit(10)
+
+	assert.Nil(t, u)
+	assert.Equal(t, time.Minute, wait, "Third request should wait for the window to pass")
+	now = now.Add(time.Minute)
+	u, wait = limiter.admit(10)
+	assert.NotNil(t, u, "Request should pass in the next minute")
+	assert.Zero(t, wait)
+}
+
+func TestLimiter_WaitsForTokensPerMinute(t *testing.T) {
+	limiter := NewLimiter(0, 0, 100)
+	now := time.Date(2025, 10, 2, 12, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+	release, err := limiter.Acquire(context.Background(), 60)
+	require.NoError(t, err)
+	release(30)
+	now = now.Add(20 * time.Second)
+
+	u, wait := limiter.admit(20)
+
+	assert.Nil(t, u)
+	assert.Equal(t, 40*time.Second, wait, "Question and answer tokens should count")
+	u, _ = limiter.admit(10)
+	assert.NotNil(t, u)
+}
+
+func TestLimiter_AdmitsNoMoreThanRequestsPerMinuteAtOnce(t *testing.T) {
+	limiter := NewLimiter(0, 3, 0)
+	now := time.Date(2025, 10, 2, 12, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+	var admitted atomic.Int32
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if u, _ := limiter.admit(1); u != nil {
+				admitted.Add(1)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(3), admitted.Load(), "Concurrent requests should not exceed the limit together")
+}
+
+func TestLimiter_StopsWaitingOnCanceledContext(t *testing.T) {
+	limiter := NewLimiter(1, 0, 0)
+	release, err := limiter.Acquire(context.Background(), 1)
+	require.NoError(t, err)
+	defer release(0)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = limiter.Acquire(ctx, 1)
+
+	require.ErrorIs(t, err, context.Canceled)
+}
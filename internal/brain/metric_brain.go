@@ -1,6 +1,7 @@
 package brain
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -22,9 +23,9 @@ func NewMetricBrain(brain Brain, s *stats.Stats) Brain {
 
 // Ask sends a question to the underlying Brain and tracks the time
 // taken to process the question.
-func (b *MetricBrain) Ask(question string) (string, error) {
+func (b *MetricBrain) Ask(ctx context.Context, question string) (string, error) {
 	start := time.Now()
-	result, err := b.origin.Ask(question)
+	result, err := b.origin.Ask(ctx, question)
 	if err != nil {
 		return "", fmt.Errorf("failed to ask question: %w", err)
 	}
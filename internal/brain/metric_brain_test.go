@@ -1,6 +1,7 @@
 package brain
 
 import (
+	"context"
 	"testing"
 
 	"github.com/cqfn/refrax/internal/stats"
@@ -10,7 +11,7 @@ import (
 func TestMetricBrain_Ask_DelegatesToOrigin(t *testing.T) {
 	claim := "Give me good Java code!"
 	brain := NewMetricBrain(NewMock(), &stats.Stats{})
-	response, err := brain.Ask(claim)
+	response, err := brain.Ask(context.Background(), claim)
 	assert.NoError(t, err)
 	assert.Equal(t, claim, response)
 }
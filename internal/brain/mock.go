@@ -1,6 +1,7 @@
 package brain
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -17,8 +18,11 @@ func NewMock(playbooks ...string) Brain {
 }
 
 // Ask processes a given question and provides a response based on the active playbook.
-// Returns an error if the question is empty or if the playbook fails to respond.
-func (b *mockBrain) Ask(question string) (string, error) {
+// Returns an error if the question is empty, if the playbook fails to respond or if the context is done.
+func (b *mockBrain) Ask(ctx context.Context, question string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", fmt.Errorf("question was not asked: %w", err)
+	}
 	if question == "" {
 		return "", fmt.Errorf("question cannot be empty")
 	}
@@ -1,6 +1,7 @@
 package brain
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -10,7 +11,7 @@ func TestAsk_ValidQuestion(t *testing.T) {
 	brain := NewMock()
 	question := "What is the capital of France?"
 
-	response, err := brain.Ask(question)
+	response, err := brain.Ask(context.Background(), question)
 
 	require.NoError(t, err)
 	require.Equal(t, question, response)
@@ -20,7 +21,7 @@ func TestAsk_EmptyQuestion(t *testing.T) {
 	brain := NewMock()
 	question := ""
 
-	response, err := brain.Ask(question)
+	response, err := brain.Ask(context.Background(), question)
 
 	require.Error(t, err)
 	require.Equal(t, "question cannot be empty", err.Error())
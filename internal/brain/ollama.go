@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"time"
+	"strings"
 
 	"github.com/cqfn/refrax/internal/log"
 	"github.com/ollama/ollama/api"
@@ -33,7 +33,7 @@ type ollamaBrain struct {
 func NewOllama(address, model, token, system string) Brain {
 	return &ollamaBrain{
 		url:       address,
-		httpCient: &http.Client{},
+		httpCient: &http.Client{Timeout: timeout},
 		token:     token,
 		model:     model,
 		system:    system,
@@ -44,21 +44,22 @@ func NewOllama(address, model, token, system string) Brain {
 //
 // This function constructs a chat request using the configured model, system prompt,
 // and the user-provided question. It sends the request to the Ollama API and waits
-// for a response or an error.
+// for the whole response, an error or the cancellation of the context.
 //
 // Parameters:
+//   - ctx (context.Context): The context that stops the request when canceled.
 //   - question (string): The user's question to be sent to the AI model.
 //
 // Returns:
 //   - string: The AI model's response to the question.
 //   - error: An error that occurred during the API interaction, or nil if successful.
-func (o *ollamaBrain) Ask(question string) (string, error) {
+func (o *ollamaBrain) Ask(ctx context.Context, question string) (string, error) {
 	address, err := url.Parse(o.url)
 	if err != nil {
 		return "", err
 	}
 	client := api.NewClient(address, o.httpCient)
-	ctx := context.Background()
+	stream := false
 	req := api.ChatRequest{
 		Model: o.model,
 		Messages: []api.Message{
@@ -71,28 +72,16 @@ func (o *ollamaBrain) Ask(question string) (string, error) {
 				Content: question,
 			},
 		},
+		Stream: &stream,
 	}
-	answ := make(chan string)
-	errc := make(chan error)
+	var answer strings.Builder
 	res := func(r api.ChatResponse) error {
 		log.Debug("Ollama response: %+v", r)
-		select {
-		case answ <- r.Message.Content:
-			return nil
-		case <-time.After(time.Minute * 5):
-			return fmt.Errorf("timeout: can't write to answer channel")
-		}
+		answer.WriteString(r.Message.Content)
+		return nil
 	}
-	go func() {
-		err := client.Chat(ctx, &req, res)
-		if err != nil {
-			errc <- err
-		}
-	}()
-	select {
-	case a := <-answ:
-		return a, nil
-	case e := <-errc:
-		return "", fmt.Errorf("error from Ollama API: %w", e)
+	if err = client.Chat(ctx, &req, res); err != nil {
+		return "", fmt.Errorf("error from Ollama API: %w", err)
 	}
+	return answer.String(), nil
 }
@@ -1,6 +1,7 @@
 package brain
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"strings"
@@ -32,7 +33,7 @@ func TestAsk_InvalidURL(t *testing.T) {
 		model:     "gemma3",
 		system:    "system-message",
 	}
-	ans, err := b.Ask("test-question")
+	ans, err := b.Ask(context.Background(), "test-question")
 	assert.Empty(t, ans)
 	assert.Error(t, err)
 }
@@ -42,6 +43,9 @@ type MockTransport struct {
 }
 
 func (m *MockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
 	return m.RoundTripFunc(req), nil
 }
 
@@ -68,7 +72,7 @@ func TestAsk_ClientError(t *testing.T) {
 		model:     "gemma3",
 		system:    "system-message",
 	}
-	_, err := ollamaBrain.Ask("test-question")
+	_, err := ollamaBrain.Ask(context.Background(), "test-question")
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "error from Ollama API")
@@ -83,7 +87,22 @@ func TestAsk_Success(t *testing.T) {
 		model:     "llama3.1",
 		system:    "system-message",
 	}
-	answ, err := ollamaBrain.Ask("test-question")
+	answ, err := ollamaBrain.Ask(context.Background(), "test-question")
 	require.NoError(t, err)
 	assert.Equal(t, "Hello", answ)
 }
+
+func TestAsk_StopsOnCanceledContext(t *testing.T) {
+	ollamaBrain := &ollamaBrain{
+		url:       "http://example.com",
+		httpCient: NeoMockClient(`{"message":{"role":"assistant","content":"Hello"},"done":true}`, 200),
+		model:     "gemma3",
+		system:    "system-message",
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := ollamaBrain.Ask(ctx, "test-question")
+
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -1,6 +1,7 @@
 package brain
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -11,9 +12,9 @@ func TestOpenAI_Ask_PositiveCase(t *testing.T) {
 	defer server.Close()
 
 	openai := NewOpenAI("test_api_key", "openai sys prompt")
-	openai.(*openAI).url = server.URL
+	openai.(*compatible).url = server.URL
 
-	answer, err := openai.Ask("This is a test question")
+	answer, err := openai.Ask(context.Background(), "This is a test question")
 
 	require.NoError(t, err)
 	require.Equal(t, "This is a test question", answer)
@@ -24,9 +25,9 @@ func TestOpenAI_Ask_NegativeCase(t *testing.T) {
 	defer server.Close()
 
 	openai := NewOpenAI("test_api_key", "openai system prompt")
-	openai.(*openAI).url = server.URL
+	openai.(*compatible).url = server.URL
 
-	answer, err := openai.Ask("This is a test question")
+	answer, err := openai.Ask(context.Background(), "This is a test question")
 
 	require.Error(t, err)
 	require.Empty(t, answer)
@@ -0,0 +1,116 @@
+package brain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/cqfn/refrax/internal/log"
+	"github.com/cqfn/refrax/internal/stats"
+	"github.com/ollama/ollama/api"
+)
+
+// maxDelay caps the exponential backoff between two attempts.
+const maxDelay = time.Minute
+
+// maxRetryAfter is the longest Retry-After delay we are ready to wait, a longer one fails the request.
+const maxRetryAfter = 5 * time.Minute
+
+// retryable lists the HTTP statuses that are safe to retry for each provider.
+var retryable = map[string][]int{
+	openai:   {408, 409, 429, 500, 502, 503, 504},
+	deepseek: {429, 500, 502, 503, 504},
+	compat:   {408, 429, 500, 502, 503, 504},
+	// Anthropic returns 529 when its API is temporarily overloaded.
+	anthropic: {408, 409, 429, 500, 502, 503, 504, 529},
+	ollama:    {429, 500, 502, 503, 504},
+}
+
+// RetryBrain is a wrapper around a Brain that repeats failed requests
+// with a jittered exponential backoff, honoring the Retry-After delays of the provider.
+type RetryBrain struct {
+	origin   Brain
+	statuses []int
+	attempts int
+	delay    time.Duration
+	stats    *stats.Stats
+}
+
+// NewRetryBrain creates a RetryBrain that retries requests to the given provider at most
+// the given number of times, starting with the given delay and doubling it after every retry.
+func NewRetryBrain(origin Brain, provider string, retries int, delay time.Duration, s *stats.Stats) Brain {
+	return &RetryBrain{
+		origin:   origin,
+		statuses: retryable[provider],
+		attempts: max(retries, 0) + 1,
+		delay:    delay,
+		stats:    s,
+	}
+}
+
+// Ask sends the question to the underlying Brain and retries the request while the error is safe to retry.
+func (b *RetryBrain) Ask(ctx context.Context, question string) (string, error) {
+	var err error
+	for attempt := 1; ; attempt++ {
+		var answer string
+		answer, err = b.origin.Ask(ctx, question)
+		if err == nil {
+			return answer, nil
+		}
+		if attempt >= b.attempts || ctx.Err() != nil || !b.safe(err) {
+			break
+		}
+		wait := b.backoff(attempt, err)
+		if wait > maxRetryAfter {
+			return "", fmt.Errorf("provider asked to retry in %s, that is too long: %w", wait, err)
+		}
+		log.Warn("LLM request failed, retrying in %s (attempt %d/%d): %v", wait, attempt+1, b.attempts, err)
+		b.stats.LLMRetry()
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("retry was canceled: %w", ctx.Err())
+		case <-time.After(wait):
+		}
+	}
+	return "", err
+}
+
+// safe reports whether the request can be repeated without side effects.
+func (b *RetryBrain) safe(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return slices.Contains(b.statuses, apiErr.Status)
+	}
+	var statusErr api.StatusError
+	if errors.As(err, &statusErr) {
+		return slices.Contains(b.statuses, statusErr.StatusCode)
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// backoff returns the delay before the next attempt: the Retry-After delay of the provider,
+// if any, or an exponential delay with a random jitter.
+func (b *RetryBrain) backoff(attempt int, err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter
+	}
+	exp := min(b.delay<<min(attempt-1, 16), maxDelay)
+	if exp <= 0 {
+		return 0
+	}
+	half := exp / 2
+	return half + rand.N(exp-half+1)
+}
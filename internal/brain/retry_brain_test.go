@@ -0,0 +1,95 @@
+package brain
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cqfn/refrax/internal/stats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryBrain_RetriesRateLimitedRequest(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"choices": [{"message": {"content": "done"}}]}`))
+	}))
+	defer server.Close()
+	origin := NewDeepSeek("token", "system")
+	origin.(*compatible).url = server.URL
+	s := &stats.Stats{}
+
+	answer, err := NewRetryBrain(origin, deepseek, 3, time.Millisecond, s).Ask(context.Background(), "question")
+
+	require.NoError(t, err)
+	assert.Equal(t, "done", answer)
+	assert.Equal(t, 2, s.TotalLLMRetries(), "Two retries should be counted")
+}
+
+func TestRetryBrain_GivesUpAfterLastAttempt(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	origin := NewOpenAI("token", "system")
+	origin.(*compatible).url = server.URL
+
+	_, err := NewRetryBrain(origin, openai, 2, time.Millisecond, &stats.Stats{}).Ask(context.Background(), "question")
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.Status)
+	assert.Equal(t, int32(3), calls.Load(), "First attempt and two retries are expected")
+}
+
+func TestRetryBrain_DoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	origin := NewOpenAI("token", "system")
+	origin.(*compatible).url = server.URL
+
+	_, err := NewRetryBrain(origin, openai, 3, time.Millisecond, &stats.Stats{}).Ask(context.Background(), "question")
+
+	require.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load(), "Unauthorized request must not be retried")
+}
+
+func TestRetryBrain_HonorsRetryAfter(t *testing.T) {
+	b := &RetryBrain{delay: time.Hour}
+
+	wait := b.backoff(1, &APIError{Status: http.StatusTooManyRequests, RetryAfter: 2 * time.Second})
+
+	assert.Equal(t, 2*time.Second, wait)
+}
+
+func TestRetryBrain_JittersExponentialBackoff(t *testing.T) {
+	b := &RetryBrain{delay: time.Second}
+
+	wait := b.backoff(3, errors.New("timeout"))
+
+	assert.GreaterOrEqual(t, wait, 2*time.Second)
+	assert.LessOrEqual(t, wait, 4*time.Second)
+}
+
+func TestRetryAfter_ParsesSecondsAndDates(t *testing.T) {
+	now := time.Date(2025, 10, 2, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 7*time.Second, retryAfter("7", now))
+	assert.Equal(t, time.Minute, retryAfter(now.Add(time.Minute).Format(http.TimeFormat), now))
+	assert.Equal(t, time.Duration(0), retryAfter("soon", now))
+}
@@ -60,3 +60,47 @@ func NewErrorServer(t *testing.T) *httptest.Server {
 		require.NoError(t, err, "Failed to write error response")
 	}))
 }
+
+// NewAnthropicServer creates a test server that imitates the Anthropic Messages API
+// and echoes back the user message
+func NewAnthropicServer(t *testing.T, expectedModel, expectedToken string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, expectedToken, r.Header.Get("x-api-key"), "Invalid API key")
+		require.NotEmpty(t, r.Header.Get("anthropic-version"), "API version is required")
+		var request struct {
+			Model     string      `json:"model"`
+			MaxTokens int         `json:"max_tokens"`
+			System    string      `json:"system"`
+			Messages  []aiMessage `json:"messages"`
+		}
+		err := json.NewDecoder(r.Body).Decode(&request)
+		require.NoError(t, err, "Failed to unmarshal request body")
+		require.Equal(t, expectedModel, request.Model, "Unexpected model")
+		require.Positive(t, request.MaxTokens, "Max tokens are required")
+		require.NotEmpty(t, request.System, "System prompt should be sent separately")
+		resp := map[string]any{
+			"type":        "message",
+			"role":        "assistant",
+			"stop_reason": "end_turn",
+			"content": []map[string]string{
+				{"type": "text", "text": request.Messages[0].Content},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(w).Encode(resp)
+		require.NoError(t, err, "Failed to write response")
+	}))
+}
+
+// NewAnthropicErrorServer creates a test server that returns a structured Anthropic error
+func NewAnthropicErrorServer(t *testing.T, status int, kind, message string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Retry-After", "3")
+		w.WriteHeader(status)
+		_, err := fmt.Fprintf(w, `{"type":"error","error":{"type":%q,"message":%q}}`, kind, message)
+		require.NoError(t, err, "Failed to write error response")
+	}))
+}
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/cqfn/refrax/internal/critic"
+	"github.com/cqfn/refrax/internal/domain"
+	"github.com/cqfn/refrax/internal/facilitator"
+	"github.com/cqfn/refrax/internal/fixer"
+	"github.com/cqfn/refrax/internal/prompts"
+	"github.com/cqfn/refrax/internal/reviewer"
+	"github.com/cqfn/refrax/internal/stats"
+	"github.com/cqfn/refrax/internal/tool"
+)
+
+func criticSystem() *prompts.System {
+	return &prompts.System{
+		AgentName:      "critic",
+		ProjectContext: "you are part of a team working on a Java project. Your role is to review Java classes and provide constructive feedback to improve code quality, maintainability, and adherence to best practices.",
+		Capabilities: []string{
+			"Analyze Java code for potential improvements",
+			"Identify code smells and suggest refactorings",
+			"Provide feedback on code structure and design patterns",
+			"Suggest improvements without altering functionality",
+		},
+		Constraints: []string{
+			"You cannot change the functionality of the code",
+			"You cannot suggest changes that require moving code between files",
+			"You cannot suggest renamimg classes or methods",
+			"You cannot suggest removing JavaDoc comments",
+		},
+	}
+}
+
+func fixerSystem() *prompts.System {
+	return &prompts.System{
+		AgentName:      "fixer",
+		ProjectContext: "you are part of a team working on a Java project. Your role is to fix Java classes based on the feedback provided by the Critic, ensuring that the code quality and maintainability are improved without altering the original functionality.",
+		Capabilities: []string{
+			"Apply suggested improvements to Java code",
+			"Refactor code to enhance readability and maintainability",
+		},
+		Constraints: []string{
+			"You cannot change the functionality of the code",
+			"You cannot change the code that require moving code between files",
+			"You cannot rename classes or methods",
+			"You cannot remove JavaDoc comments",
+		},
+	}
+}
+
+func reviewerSystem() *prompts.System {
+	return &prompts.System{
+		AgentName:      "reviewer",
+		ProjectContext: "you are part of a team working on a Java project. Your role is to review the refactored Java classes to ensure that the applied changes align with the original suggestions provided by the Critic and that the code quality has been improved without altering the original functionality.",
+		Capabilities: []string{
+			"Run build and test commands to validate code changes",
+			"Provide feedback on the success or failure of the build and tests",
+			"Suggest further improvements based on build and test results",
+		},
+		Constraints: []string{
+			"You cannot suggest changes that require moving code between files",
+			"You cannot suggest adding another dependencies",
+		},
+	}
+}
+
+func facilitatorSystem() *prompts.System {
+	return &prompts.System{
+		AgentName:      "facilitator",
+		ProjectContext: "you are part of a team working on a Java project. Your role is to facilitate the refactoring process by coordinating between the Critic, Fixer, and Reviewer agents to ensure that Java classes are effectively improved while maintaining their original functionality.",
+		Capabilities: []string{
+			"Understand the most important suggestions from the Critic",
+			"Group and prioritize suggestions for the Fixer",
+		},
+		Constraints: []string{
+			"You cannot change suggestions",
+		},
+	}
+}
+
+func newCritic(p Params, port int, s *stats.Stats) (*critic.Critic, error) {
+	p = p.role("critic")
+	system := criticSystem()
+	system.Constraints = append(system.Constraints, p.Constraints...)
+	ai, err := mind(p, system, s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AI instance for critic: %w", err)
+	}
+	tools, err := tool.New(p.Tools...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tools for critic: %w", err)
+	}
+	ctc := critic.NewCritic(ai, port, p.Colorless, tools...)
+	ctc.Handler(countStats(s))
+	return ctc, nil
+}
+
+func newFixer(p Params, port int, s *stats.Stats) (*fixer.Fixer, error) {
+	p = p.role("fixer")
+	ai, err := mind(p, fixerSystem(), s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AI instance for fixer: %w", err)
+	}
+	fxr := fixer.NewFixer(ai, port, p.Colorless)
+	fxr.Handler(countStats(s))
+	return fxr, nil
+}
+
+func newReviewer(p Params, port int, s *stats.Stats) (*reviewer.A2AReviewer, error) {
+	p = p.role("reviewer")
+	ai, err := mind(p, reviewerSystem(), s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AI instance for reviewer: %w", err)
+	}
+	rvwr := reviewer.NewReviewer(ai, port, p.Colorless, p.Checks...)
+	rvwr.Handler(countStats(s))
+	return rvwr, nil
+}
+
+func newFacilitator(
+	p Params, port int, s *stats.Stats, ctc domain.Critic, fxr domain.Fixer, rvwr domain.Reviewer,
+) (*facilitator.A2AFacilitator, error) {
+	p = p.role("facilitator")
+	ai, err := mind(p, facilitatorSystem(), s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AI instance for facilitator: %w", err)
+	}
+	fclttor := facilitator.NewFacilitator(ai, ctc, fxr, rvwr, port, p.Colorless, p.Attempts)
+	fclttor.Concurrency(p.MaxConcurrency)
+	fclttor.Handler(countStats(s))
+	return fclttor, nil
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cqfn/refrax/internal/domain"
+	"github.com/cqfn/refrax/internal/git"
+	"github.com/cqfn/refrax/internal/log"
+)
+
+// committer returns a hook that commits every refactoring round to the git repository of the project.
+// If a branch is given, it is created first, so that the commits don't land on the current branch.
+func committer(p Params) (func(domain.Round) error, error) {
+	root := p.Input
+	if p.Output != "" {
+		root = p.Output
+	}
+	repo, err := git.Open(root)
+	if err != nil {
+		return nil, fmt.Errorf("can't commit refactoring rounds: %w", err)
+	}
+	if p.Branch != "" {
+		if err = repo.Branch(p.Branch); err != nil {
+			return nil, err
+		}
+		log.Info("Refactoring rounds will be committed to the new branch %s", p.Branch)
+	}
+	return func(round domain.Round) error {
+		files := make([]string, 0, len(round.Changes))
+		for _, c := range round.Changes {
+			files = append(files, c.Class.Path())
+		}
+		done, cerr := repo.Commit(message(repo.Root(), round), files...)
+		if cerr != nil {
+			return cerr
+		}
+		if done {
+			log.Info("Committed refactoring round %d with %d classes", round.Number, len(files))
+		} else {
+			log.Info("Refactoring round %d changed nothing, no commit", round.Number)
+		}
+		return nil
+	}, nil
+}
+
+// message describes the round: the suggestions applied to each class and the diff sizes.
+func message(root string, round domain.Round) string {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "Refactoring round %d: %d classes, diff %d\n", round.Number, len(round.Changes), round.Diff())
+	for _, c := range round.Changes {
+		path := c.Class.Path()
+		if abs, err := filepath.Abs(path); err == nil {
+			if rel, rerr := filepath.Rel(root, abs); rerr == nil {
+				path = filepath.ToSlash(rel)
+			}
+		}
+		fmt.Fprintf(&msg, "\n%s (diff %d):\n", path, c.Diff)
+		for _, s := range c.Suggestions {
+			fmt.Fprintf(&msg, "- %s\n", s.Text)
+		}
+	}
+	return msg.String()
+}
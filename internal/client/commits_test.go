@@ -0,0 +1,43 @@
+package client
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cqfn/refrax/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessage_ListsSuggestionsAndDiffsOfClasses(t *testing.T) {
+	root := t.TempDir()
+	foo := filepath.Join(root, "src", "Foo.java")
+	bar := filepath.Join(root, "src", "Bar.java")
+	round := domain.Round{
+		Number: 2,
+		Changes: []domain.Change{
+			{
+				Class:       domain.NewFSClass("Foo", foo),
+				Suggestions: []domain.Suggestion{*domain.NewSuggestion("Make the class final", foo)},
+				Diff:        3,
+			},
+			{
+				Class: domain.NewFSClass("Bar", bar),
+				Suggestions: []domain.Suggestion{
+					*domain.NewSuggestion("Remove the unused import", bar),
+					*domain.NewSuggestion("Inline the variable", bar),
+				},
+				Diff: 4,
+			},
+		},
+	}
+
+	msg := message(root, round)
+
+	assert.Equal(
+		t,
+		"Refactoring round 2: 2 classes, diff 7\n\n"+
+			"src/Foo.java (diff 3):\n- Make the class final\n\n"+
+			"src/Bar.java (diff 4):\n- Remove the unused import\n- Inline the variable\n",
+		msg,
+	)
+}
@@ -1,45 +1,196 @@
 package client
 
-import "io"
+import (
+	"io"
+	"time"
+
+	"github.com/cqfn/refrax/internal/brain"
+)
 
 // Params holds the configuration parameters for Refrax commands.
 type Params struct {
-	Provider    string
-	Token       string
-	Playbook    string
-	MockProject bool
-	Debug       bool
-	Stats       bool
-	Format      string
-	Soutput     string
-	Input       string
-	Output      string
-	MaxSize     int
-	Log         io.Writer
-	Checks      []string
-	Colorless   bool
-	Model       string
-	Attempts    int
+	Provider       string
+	Token          string
+	Playbook       string
+	MockProject    bool
+	Debug          bool
+	Stats          bool
+	Format         string
+	Soutput        string
+	Input          string
+	Output         string
+	Since          string
+	Staged         bool
+	Commit         bool
+	DryRun         bool
+	Patch          string
+	Branch         string
+	MaxSize        int
+	Prioritizer    string
+	Weights        string
+	FixMode        string
+	TokenLimit     int
+	Examples       []string
+	Log            io.Writer
+	Checks         []string
+	Include        []string
+	Exclude        []string
+	Constraints    []string
+	Tools          []string
+	Colorless      bool
+	Model          string
+	MaxTokens      int
+	BaseURL        string
+	Temperature    float64
+	Attempts       int
+	Retries        int
+	RetryDelay     time.Duration
+	MaxConcurrency int
+	RPM            int
+	TPM            int
+	Port           int
+	CriticURL      string
+	FixerURL       string
+	ReviewerURL    string
+	FacilitatorURL string
+	Critic         Role
+	Fixer          Role
+	Reviewer       Role
+	Facilitator    Role
+
+	// limiter is shared by all brains of a run, it is created from the limits above.
+	limiter *brain.Limiter
+}
+
+// Role holds the AI settings of a single agent, they override the global ones when set.
+type Role struct {
+	Provider string
+	Model    string
+	Token    string
+}
+
+// role returns a copy of the params with the AI settings of the given agent applied.
+// An agent with its own provider doesn't inherit the global model, token and base URL,
+// since they belong to another provider.
+func (p Params) role(name string) Params {
+	r, ok := p.roles()[name]
+	if !ok {
+		return p
+	}
+	if r.Provider != "" && r.Provider != p.Provider {
+		p.Provider = r.Provider
+		p.Model = ""
+		p.Token = ""
+		p.BaseURL = ""
+	}
+	if r.Model != "" {
+		p.Model = r.Model
+	}
+	if r.Token != "" {
+		p.Token = r.Token
+	}
+	return p
+}
+
+// roles returns the AI settings of the agents by their names.
+func (p *Params) roles() map[string]*Role {
+	return map[string]*Role{
+		"critic":      &p.Critic,
+		"fixer":       &p.Fixer,
+		"reviewer":    &p.Reviewer,
+		"facilitator": &p.Facilitator,
+	}
+}
+
+// authorize returns a copy of the params where the given agents have their tokens found,
+// so that a missing token fails the run before any agent starts.
+func (p Params) authorize(names ...string) (Params, error) {
+	for _, name := range names {
+		tkn, err := token(p.role(name))
+		if err != nil {
+			return p, err
+		}
+		if r, ok := p.roles()[name]; ok {
+			r.Token = tkn
+		}
+	}
+	return p, nil
+}
+
+// locals returns the names of the agents that a refactoring starts locally.
+func (p Params) locals() []string {
+	if p.FacilitatorURL != "" {
+		return []string{}
+	}
+	res := []string{"facilitator"}
+	if p.CriticURL == "" {
+		res = append(res, "critic")
+	}
+	if p.FixerURL == "" {
+		res = append(res, "fixer")
+	}
+	if p.ReviewerURL == "" {
+		res = append(res, "reviewer")
+	}
+	return res
+}
+
+// limited returns a copy of the params with a limiter that all brains of a run share.
+func (p Params) limited() Params {
+	p.limiter = brain.NewLimiter(p.MaxConcurrency, p.RPM, p.TPM)
+	return p
 }
 
 // NewMockParams creates a new Params object with mock settings.
 func NewMockParams() *Params {
 	return &Params{
-		Provider:    "mock",
-		Token:       "ABC",
-		Playbook:    "",
-		MockProject: true,
-		Debug:       false,
-		Stats:       false,
-		Format:      "std",
-		Soutput:     "stats",
-		Input:       "",
-		Output:      "",
-		MaxSize:     200,
-		Log:         io.Discard,
-		Checks:      []string{"mvn clean test"},
-		Colorless:   false,
-		Model:       "gpt-3.5-turbo",
-		Attempts:    3,
+		Provider:       "mock",
+		Token:          "ABC",
+		Playbook:       "",
+		MockProject:    true,
+		Debug:          false,
+		Stats:          false,
+		Format:         "std",
+		Soutput:        "stats",
+		Input:          "",
+		Output:         "",
+		Since:          "",
+		Staged:         false,
+		Commit:         false,
+		DryRun:         false,
+		Patch:          "",
+		Branch:         "",
+		MaxSize:        200,
+		Prioritizer:    "local",
+		Weights:        "",
+		FixMode:        "full",
+		TokenLimit:     6_000,
+		Examples:       []string{},
+		Log:            io.Discard,
+		Checks:         []string{"mvn clean test"},
+		Include:        []string{},
+		Exclude:        []string{},
+		Constraints:    []string{},
+		Tools:          []string{},
+		Colorless:      false,
+		Model:          "gpt-3.5-turbo",
+		MaxTokens:      0,
+		BaseURL:        "",
+		Temperature:    -1,
+		Attempts:       3,
+		Retries:        0,
+		RetryDelay:     time.Second,
+		MaxConcurrency: 0,
+		RPM:            0,
+		TPM:            0,
+		Port:           0,
+		CriticURL:      "",
+		FixerURL:       "",
+		ReviewerURL:    "",
+		FacilitatorURL: "",
+		Critic:         Role{},
+		Fixer:          Role{},
+		Reviewer:       Role{},
+		Facilitator:    Role{},
 	}
 }
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParams_Role_InheritsGlobalSettings(t *testing.T) {
+	params := NewMockParams()
+	params.Fixer = Role{Model: "gpt-4o"}
+
+	fixer := params.role("fixer")
+
+	assert.Equal(t, "mock", fixer.Provider)
+	assert.Equal(t, "gpt-4o", fixer.Model)
+	assert.Equal(t, "ABC", fixer.Token)
+}
+
+func TestParams_Role_DropsSettingsOfAnotherProvider(t *testing.T) {
+	params := NewMockParams()
+	params.BaseURL = "http://localhost:8000/v1"
+	params.Critic = Role{Provider: "ollama"}
+
+	critic := params.role("critic")
+
+	assert.Equal(t, "ollama", critic.Provider)
+	assert.Empty(t, critic.Model)
+	assert.Empty(t, critic.Token)
+	assert.Empty(t, critic.BaseURL)
+}
+
+func TestParams_Role_KeepsGlobalSettingsOfOtherAgents(t *testing.T) {
+	params := NewMockParams()
+	params.Critic = Role{Provider: "ollama", Model: "qwen2.5-coder"}
+
+	reviewer := params.role("reviewer")
+
+	assert.Equal(t, "mock", reviewer.Provider)
+	assert.Equal(t, "gpt-3.5-turbo", reviewer.Model)
+}
+
+func TestParams_Authorize_FindsTokenOfEachAgent(t *testing.T) {
+	t.Setenv("OLLAMA_TOKEN", "ollama-token")
+	params := NewMockParams()
+	params.Critic = Role{Provider: "ollama"}
+
+	authorized, err := params.authorize(params.locals()...)
+
+	require.NoError(t, err)
+	assert.Equal(t, "ollama-token", authorized.role("critic").Token)
+	assert.Equal(t, "ABC", authorized.role("fixer").Token)
+}
+
+func TestParams_Authorize_FailsWithoutToken(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+	params := NewMockParams()
+	params.Fixer = Role{Provider: "openai"}
+
+	_, err := params.authorize("fixer")
+
+	require.Error(t, err)
+}
+
+func TestParams_Locals_SkipsRemoteAgents(t *testing.T) {
+	params := NewMockParams()
+	params.CriticURL = "http://localhost:8081"
+
+	assert.Equal(t, []string{"facilitator", "fixer", "reviewer"}, params.locals())
+}
@@ -3,22 +3,21 @@ package client
 import (
 	"encoding/json"
 	"fmt"
-	"net/http"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/cqfn/refrax/internal/brain"
-	"github.com/cqfn/refrax/internal/critic"
 	"github.com/cqfn/refrax/internal/domain"
 	"github.com/cqfn/refrax/internal/env"
-	"github.com/cqfn/refrax/internal/facilitator"
-	"github.com/cqfn/refrax/internal/fixer"
+	"github.com/cqfn/refrax/internal/git"
 	"github.com/cqfn/refrax/internal/log"
 	"github.com/cqfn/refrax/internal/prompts"
 	"github.com/cqfn/refrax/internal/protocol"
-	"github.com/cqfn/refrax/internal/reviewer"
 	"github.com/cqfn/refrax/internal/stats"
-	"github.com/cqfn/refrax/internal/util"
 )
 
 // RefraxClient represents a client used for refactoring projects.
@@ -30,7 +29,7 @@ type RefraxClient struct {
 func NewRefraxClient(params *Params) *RefraxClient {
 	initLogger(params)
 	return &RefraxClient{
-		params: *params,
+		params: params.limited(),
 	}
 }
 
@@ -46,6 +45,14 @@ func Refactor(params *Params) (domain.Project, error) {
 // Refactor performs refactoring on the given project using the RefraxClient.
 func (c *RefraxClient) Refactor(proj domain.Project) (domain.Project, error) {
 	log.Debug("Starting refactoring for project %s", proj)
+	var overlay *domain.OverlayProject
+	if c.params.DryRun {
+		if c.params.Output != "" || c.params.Commit || c.params.Branch != "" {
+			return nil, fmt.Errorf("--dry-run can't be used with --output, --commit or --branch")
+		}
+		overlay = domain.NewOverlayProject(c.params.Input, proj)
+		proj = overlay
+	}
 	classes, err := proj.Classes()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get classes from project %s: %w", proj, err)
@@ -55,174 +62,128 @@ func (c *RefraxClient) Refactor(proj domain.Project) (domain.Project, error) {
 	}
 	log.Debug("Found %d classes in the project: %v", len(classes), classes)
 
-	criticStats := &stats.Stats{Name: "critic"}
-	criticSystemPrompt := prompts.System{
-		AgentName:      "critic",
-		ProjectContext: "you are part of a team working on a Java project. Your role is to review Java classes and provide constructive feedback to improve code quality, maintainability, and adherence to best practices.",
-		Capabilities: []string{
-			"Analyze Java code for potential improvements",
-			"Identify code smells and suggest refactorings",
-			"Provide feedback on code structure and design patterns",
-			"Suggest improvements without altering functionality",
-		},
-		Constraints: []string{
-			"You cannot change the functionality of the code",
-			"You cannot suggest changes that require moving code between files",
-			"You cannot suggest renamimg classes or methods",
-			"You cannot suggest removing JavaDoc comments",
-		},
-	}
-	token, err := token(c.params)
+	params, err := c.params.authorize(c.params.locals()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find token: %w", err)
 	}
-	model := c.params.Model
-	log.Debug("Using model: %s", model)
-	criticBrain, err := mind(c.params, token, model, &criticSystemPrompt, criticStats)
+	log.Debug("Using model: %s", params.Model)
+	fclttor, members, err := facilitate(params)
+	defer members.shutdown()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create AI instance: %w", err)
+		return nil, fmt.Errorf("failed to prepare agents: %w", err)
 	}
-	criticPort, err := util.FreePort()
+	log.Info("All agents are ready")
+	log.Info("Begin refactoring for project %s with %d classes", proj, len(classes))
+	examples, err := exemplars(params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find free port for critic: %w", err)
+		return nil, fmt.Errorf("failed to find example classes: %w", err)
 	}
-	ctc := critic.NewCritic(criticBrain, criticPort, c.params.Colorless)
-	ctc.Handler(countStats(criticStats))
-
-	fixerStats := &stats.Stats{Name: "fixer"}
-	fixerSystemPrompt := prompts.System{
-		AgentName:      "fixer",
-		ProjectContext: "you are part of a team working on a Java project. Your role is to fix Java classes based on the feedback provided by the Critic, ensuring that the code quality and maintainability are improved without altering the original functionality.",
-		Capabilities: []string{
-			"Apply suggested improvements to Java code",
-			"Refactor code to enhance readability and maintainability",
-		},
-		Constraints: []string{
-			"You cannot change the functionality of the code",
-			"You cannot change the code that require moving code between files",
-			"You cannot rename classes or methods",
-			"You cannot remove JavaDoc comments",
-		},
+	ch := make(chan refactoring, len(classes))
+	go refactor(fclttor, proj, meta(params), examples, ch)
+	for range len(classes) {
+		res, ok := <-ch
+		if !ok {
+			break
+		}
+		if res.err != nil {
+			return nil, res.err
+		}
+		if res.class != nil && res.content != "" {
+			log.Info("Received refactored class: %s, content length: %d", res.class.Name(), len(res.content))
+			if overlay != nil || params.FacilitatorURL != "" {
+				if err = res.class.SetContent(res.content); err != nil {
+					return nil, fmt.Errorf("failed to keep refactored class %s: %w", res.class.Name(), err)
+				}
+			}
+		}
 	}
-	fixerBrain, err := mind(c.params, token, model, &fixerSystemPrompt, fixerStats)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create AI instance: %w", err)
+	log.Info("Refactoring is finished")
+	if overlay != nil {
+		if err = patch(overlay, params); err != nil {
+			return nil, err
+		}
 	}
-	fixerPort, err := util.FreePort()
+	err = printStats(params, members.stats...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find free port for fixer: %w", err)
+		return nil, fmt.Errorf("failed to print statistics: %w", err)
 	}
-	fxr := fixer.NewFixer(fixerBrain, fixerPort, c.params.Colorless)
-	fxr.Handler(countStats(fixerStats))
+	return proj, err
+}
 
-	reviewerStats := &stats.Stats{Name: "reviewer"}
-	reviewerSystemPrompt := prompts.System{
-		AgentName:      "reviewer",
-		ProjectContext: "you are part of a team working on a Java project. Your role is to review the refactored Java classes to ensure that the applied changes align with the original suggestions provided by the Critic and that the code quality has been improved without altering the original functionality.",
-		Capabilities: []string{
-			"Run build and test commands to validate code changes",
-			"Provide feedback on the success or failure of the build and tests",
-			"Suggest further improvements based on build and test results",
-		},
-		Constraints: []string{
-			"You cannot suggest changes that require moving code between files",
-			"You cannot suggest adding another dependencies",
-		},
+type refactoring struct {
+	class   domain.Class
+	content string
+	err     error
+}
+
+// meta returns the parameters of the refactoring job for the facilitator.
+func meta(p Params) map[string]any {
+	res := map[string]any{
+		"max-size": fmt.Sprintf("%d", p.MaxSize),
 	}
-	reviewerBrain, err := mind(c.params, token, model, &reviewerSystemPrompt, reviewerStats)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create AI instance for reviewer: %w", err)
+	if p.TokenLimit > 0 {
+		res["token-limit"] = fmt.Sprintf("%d", p.TokenLimit)
 	}
-	reviewerPort, err := util.FreePort()
-	if err != nil {
-		return nil, fmt.Errorf("failed to find free port for reviewer: %w", err)
+	if p.Prioritizer != "" {
+		res["prioritizer"] = p.Prioritizer
 	}
-	rvwr := reviewer.NewReviewer(reviewerBrain, reviewerPort, c.params.Colorless, c.params.Checks...)
-	rvwr.Handler(countStats(reviewerStats))
-
-	facilitatorStats := &stats.Stats{Name: "facilitator"}
-	facilitatorSystemPrompt := prompts.System{
-		AgentName:      "facilitator",
-		ProjectContext: "you are part of a team working on a Java project. Your role is to facilitate the refactoring process by coordinating between the Critic, Fixer, and Reviewer agents to ensure that Java classes are effectively improved while maintaining their original functionality.",
-		Capabilities: []string{
-			"Understand the most important suggestions from the Critic",
-			"Group and prioritize suggestions for the Fixer",
-		},
-		Constraints: []string{
-			"You cannot change suggestions",
-		},
+	if p.Weights != "" {
+		res["weights"] = p.Weights
 	}
-	facilitatorBrain, err := mind(c.params, token, model, &facilitatorSystemPrompt, facilitatorStats)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create AI instance: %w", err)
+	if slices.Contains(p.Examples, "auto") {
+		res["examples"] = "auto"
 	}
-	facilitatorPort, err := util.FreePort()
-	if err != nil {
-		return nil, fmt.Errorf("failed to find free port for facilitator: %w", err)
+	if p.FixMode != "" {
+		res["fix-mode"] = p.FixMode
 	}
-	fclttor := facilitator.NewFacilitator(facilitatorBrain, ctc, fxr, rvwr, facilitatorPort, c.params.Colorless, c.params.Attempts)
-	fclttor.Handler(countStats(facilitatorStats))
-
-	go func() {
-		faerr := fclttor.ListenAndServe()
-		if faerr != nil && faerr != http.ErrServerClosed {
-			panic(fmt.Sprintf("Failed to start facilitator server: %v", faerr))
-		}
-	}()
-	go func() {
-		ferr := fxr.ListenAndServe()
-		if ferr != nil && ferr != http.ErrServerClosed {
-			panic(fmt.Sprintf("Failed to start fixer server: %v", ferr))
-		}
-	}()
-	go func() {
-		cerr := ctc.ListenAndServe()
-		if cerr != nil && cerr != http.ErrServerClosed {
-			panic(fmt.Sprintf("Failed to start critic server: %v", cerr))
-		}
-	}()
-	go func() {
-		rerr := rvwr.ListenAndServe()
-		if rerr != nil && rerr != http.ErrServerClosed {
-			panic(fmt.Sprintf("Failed to start reviewer server: %v", rerr))
+	if p.DryRun {
+		res["dry-run"] = "true"
+		if dir, err := filepath.Abs(p.Input); err == nil {
+			res["dir"] = dir
 		}
-	}()
-
-	defer shutdown(ctc)
-	defer shutdown(fclttor)
-	defer shutdown(fxr)
-	defer shutdown(rvwr)
+	}
+	return res
+}
 
-	<-ctc.Ready()
-	<-fclttor.Ready()
-	<-fxr.Ready()
-	<-rvwr.Ready()
+// patch writes the unified diff of the changes kept in the overlay to the patch file, or to the log
+// if no file is given.
+func patch(overlay *domain.OverlayProject, p Params) error {
+	diff, err := overlay.Patch()
+	if err != nil {
+		return fmt.Errorf("failed to build patch: %w", err)
+	}
+	if p.Patch == "" {
+		_, err = io.WriteString(p.Log, diff)
+		return err
+	}
+	if err = os.WriteFile(p.Patch, []byte(diff), 0o600); err != nil {
+		return fmt.Errorf("failed to write patch to %s: %w", p.Patch, err)
+	}
+	log.Info("Patch with the changes is written to %s, the project is left intact", p.Patch)
+	return nil
+}
 
-	log.Info("All servers are ready: facilitator %d, critic %d, fixer %d, reviewer %d", facilitatorPort, criticPort, fixerPort, reviewerPort)
-	log.Info("Begin refactoring for project %s with %d classes", proj, len(classes))
-	ch := make(chan refactoring, len(classes))
-	go refactor(fclttor, proj, c.params.MaxSize, ch)
-	for range len(classes) {
-		res := <-ch
-		if res.class != nil && res.content != "" {
-			log.Info("Received refactored class: %s, content length: %d", res.class.Name(), len(res.content))
+// exemplars returns the classes that match the example patterns, the fixer follows their style.
+// The "auto" pattern leaves the choice to the facilitator.
+func exemplars(p Params) ([]domain.Class, error) {
+	patterns := make([]string, 0, len(p.Examples))
+	for _, e := range p.Examples {
+		if e != "auto" {
+			patterns = append(patterns, e)
 		}
 	}
-	log.Info("Refactoring is finished")
-	err = printStats(c.params, criticStats, fixerStats, facilitatorStats)
+	if len(patterns) == 0 || p.MockProject {
+		return nil, nil
+	}
+	res, err := domain.NewFilesystem(p.Input).WithInclude(patterns...).Classes()
 	if err != nil {
-		return nil, fmt.Errorf("failed to print statistics: %w", err)
+		return nil, fmt.Errorf("failed to find classes matching %v: %w", patterns, err)
 	}
-	return proj, err
-}
-
-type refactoring struct {
-	class   domain.Class
-	content string
-	err     error
+	log.Info("Found %d example classes", len(res))
+	return res, nil
 }
 
-func refactor(f domain.Facilitator, p domain.Project, size int, ch chan<- refactoring) {
+func refactor(f domain.Facilitator, p domain.Project, meta map[string]any, examples []domain.Class, ch chan<- refactoring) {
 	log.Debug("Refactoring project %q", p)
 	all, err := p.Classes()
 	if err != nil {
@@ -232,16 +193,15 @@ func refactor(f domain.Facilitator, p domain.Project, size int, ch chan<- refact
 	}
 	before := make(map[string]domain.Class)
 	for _, c := range all {
-		before[c.Name()] = c
+		before[c.Path()] = c
 	}
 	job := domain.Job{
 		Descr: &domain.Description{
 			Text: "refactor the project",
-			Meta: map[string]any{
-				"max-size": fmt.Sprintf("%d", size),
-			},
+			Meta: meta,
 		},
-		Classes: all,
+		Classes:  all,
+		Examples: examples,
 	}
 	artifacts, err := f.Refactor(&job)
 	if err != nil {
@@ -252,9 +212,12 @@ func refactor(f domain.Facilitator, p domain.Project, size int, ch chan<- refact
 	}
 	refactored := artifacts.Classes
 	log.Info("Refactored %d classes in project %s", len(refactored), p)
+	if artifacts.Descr != nil {
+		log.Info("Facilitator finished: %s", artifacts.Descr.Text)
+	}
 	for _, c := range refactored {
 		log.Debug("Received refactored class: ", c)
-		ch <- refactoring{class: before[c.Name()], content: c.Content(), err: nil}
+		ch <- refactoring{class: before[c.Path()], content: c.Content(), err: nil}
 	}
 	close(ch)
 }
@@ -304,12 +267,41 @@ func printStats(p Params, s ...*stats.Stats) error {
 	return nil
 }
 
-func mind(p Params, token, model string, system *prompts.System, s *stats.Stats) (brain.Brain, error) {
-	ai, err := brain.New(p.Provider, token, model, system.String(), p.Playbook)
+// mind creates the brain of an agent from the params with the AI settings of that agent.
+func mind(p Params, system *prompts.System, s *stats.Stats) (brain.Brain, error) {
+	s.Model = model(p)
+	cfg := brain.Config{
+		Provider:  p.Provider,
+		BaseURL:   p.BaseURL,
+		Token:     p.Token,
+		Model:     p.Model,
+		System:    system.String(),
+		Playbook:  p.Playbook,
+		MaxTokens: p.MaxTokens,
+	}
+	if p.Temperature >= 0 {
+		cfg.Temperature = &p.Temperature
+	}
+	ai, err := brain.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if p.limiter != nil {
+		ai = brain.NewLimitedBrain(ai, p.limiter)
+	}
+	ai = brain.NewRetryBrain(ai, p.Provider, p.Retries, p.RetryDelay, s)
 	if p.Stats {
 		ai = brain.NewMetricBrain(ai, s)
 	}
-	return ai, err
+	return ai, nil
+}
+
+// model describes the provider and the model that an agent uses, e.g. "ollama/qwen2.5-coder".
+func model(p Params) string {
+	if p.Model == "" {
+		return p.Provider
+	}
+	return p.Provider + "/" + p.Model
 }
 
 func token(p Params) (string, error) {
@@ -322,6 +314,10 @@ func token(p Params) (string, error) {
 		log.Info("Token not provided, trying to find token in .env file")
 		token = env.Token(".env", p.Provider)
 	}
+	if token == "" && p.Provider == "openai-compatible" {
+		log.Info("Token not found, sending requests to %s without authorization", p.BaseURL)
+		return "", nil
+	}
 	if token == "" {
 		return "", fmt.Errorf("token not found, please provide it via --token flag or in .env file")
 	}
@@ -334,14 +330,66 @@ func proj(params Params) (domain.Project, error) {
 		log.Debug("Using mock project")
 		return domain.NewMock(), nil
 	}
-	input := domain.NewFilesystem(params.Input)
+	input := domain.NewFilesystem(params.Input).WithInclude(params.Include...).WithExclude(params.Exclude...)
+	var res domain.Project = input
+	root := params.Input
 	output := params.Output
 	if output != "" {
 		log.Debug("Copy project to %q", output)
-		return domain.NewMirrorProject(input, output)
+		mirror, err := domain.NewMirrorProject(input, output)
+		if err != nil {
+			return nil, err
+		}
+		res = mirror
+		root = output
+	} else {
+		log.Debug("No output path provided, changing project in place %q", params.Input)
+	}
+	if params.Since == "" && !params.Staged {
+		return res, nil
+	}
+	files, err := changes(params)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Found %d changed files in %q", len(files), params.Input)
+	return domain.NewChangedProject(res, root, files...), nil
+}
+
+// changes finds the files changed in the git repository of the project, relative to the project root.
+func changes(params Params) ([]string, error) {
+	if params.Since != "" && params.Staged {
+		return nil, fmt.Errorf("--since and --staged can't be used together")
+	}
+	repo, err := git.Open(params.Input)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	if params.Staged {
+		files, err = repo.Staged()
+	} else {
+		files, err = repo.Since(params.Since)
+	}
+	if err != nil {
+		return nil, err
+	}
+	root, err := filepath.Abs(params.Input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project path %s: %w", params.Input, err)
+	}
+	if resolved, rerr := filepath.EvalSymlinks(root); rerr == nil {
+		root = resolved
+	}
+	res := make([]string, 0, len(files))
+	for _, f := range files {
+		rel, rerr := filepath.Rel(root, f)
+		if rerr != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		res = append(res, rel)
 	}
-	log.Debug("No output path provided, changing project in place %q", params.Input)
-	return input, nil
+	return res, nil
 }
 
 func mask(token string) string {
@@ -3,10 +3,13 @@ package client
 import (
 	"bytes"
 	"io"
+	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 
 	"github.com/cqfn/refrax/internal/domain"
+	"github.com/cqfn/refrax/internal/stats"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -36,6 +39,16 @@ func TestRefraxClient_Refactors_EmptyProject(t *testing.T) {
 	assert.Equal(t, "no java classes found in the project [empty project], add java files to the appropriate directory", err.Error(), "Error message should indicate no classes found")
 }
 
+func TestRefraxClient_FailsWhenFacilitatorFails(t *testing.T) {
+	params := NewMockParams()
+	params.FixMode = "unknown"
+
+	_, err := NewRefraxClient(params).Refactor(domain.NewMock())
+
+	require.Error(t, err, "Failure of the facilitator should be returned")
+	assert.Contains(t, err.Error(), "unknown fix mode")
+}
+
 // TestRefraxClient_Refactors_SingleClass tests the refactoring of a single class
 // @todo #81:90min Enable TestRefraxClient_PrintsStatsIfEnabled test
 // This test is currently skipped because recent huge changes in the review strategy
@@ -68,3 +81,113 @@ func (s *SyncWriter) Write(p []byte) (n int, err error) {
 	defer s.mu.Unlock()
 	return s.w.Write(p)
 }
+
+func TestRefraxClient_DryRun_RejectsOutput(t *testing.T) {
+	params := NewMockParams()
+	params.DryRun = true
+	params.Output = t.TempDir()
+
+	_, err := NewRefraxClient(params).Refactor(domain.NewMock())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--dry-run can't be used with --output")
+}
+
+func TestMeta_PassesDryRunWithProjectDirectory(t *testing.T) {
+	params := NewMockParams()
+	params.DryRun = true
+	params.Input = t.TempDir()
+
+	m := meta(*params)
+
+	assert.Equal(t, "true", m["dry-run"])
+	assert.Equal(t, params.Input, m["dir"])
+	assert.Equal(t, "200", m["max-size"])
+}
+
+func TestMeta_PassesPrioritizerWithWeights(t *testing.T) {
+	params := NewMockParams()
+	params.Weights = "severity=2,spread=0"
+
+	m := meta(*params)
+
+	assert.Equal(t, "local", m["prioritizer"])
+	assert.Equal(t, "severity=2,spread=0", m["weights"])
+}
+
+func TestMeta_PassesFixMode(t *testing.T) {
+	params := NewMockParams()
+	params.FixMode = "edits"
+
+	m := meta(*params)
+
+	assert.Equal(t, "edits", m["fix-mode"])
+}
+
+func TestMeta_PassesAutoExamples(t *testing.T) {
+	params := NewMockParams()
+	params.Examples = []string{"auto"}
+
+	m := meta(*params)
+
+	assert.Equal(t, "auto", m["examples"])
+}
+
+func TestExemplars_FindsClassesByPatterns(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "src", "model"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "src", "model", "Order.java"), []byte("class Order {}"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "src", "Main.java"), []byte("class Main {}"), 0o600))
+	params := NewMockParams()
+	params.MockProject = false
+	params.Input = dir
+	params.Examples = []string{"auto", "src/model/**"}
+
+	res, err := exemplars(*params)
+
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.Equal(t, "Order", res[0].Name())
+}
+
+func TestRefactor_MatchesRefactoredClassesByPath(t *testing.T) {
+	proj := domain.NewInMemory(
+		domain.NewInMemoryClass("Util", "src/a/Util.java", "class Util {}"),
+		domain.NewInMemoryClass("Util", "src/b/Util.java", "class Util {}"),
+	)
+	ch := make(chan refactoring, 2)
+
+	refactor(&renaming{}, proj, nil, nil, ch)
+
+	got := make(map[string]string)
+	for res := range ch {
+		require.NoError(t, res.err)
+		got[res.class.Path()] = res.content
+	}
+	assert.Equal(t, map[string]string{
+		"src/a/Util.java": "// src/a/Util.java\nclass Util {}",
+		"src/b/Util.java": "// src/b/Util.java\nclass Util {}",
+	}, got)
+}
+
+// renaming is a facilitator that marks every class with its path.
+type renaming struct{}
+
+func (r *renaming) Refactor(job *domain.Job) (*domain.Artifacts, error) {
+	res := &domain.Artifacts{Descr: &domain.Description{Text: "done"}}
+	for _, c := range job.Classes {
+		res.Classes = append(res.Classes, domain.NewInMemoryClass(c.Name(), c.Path(), "// "+c.Path()+"\n"+c.Content()))
+	}
+	return res, nil
+}
+
+func TestMind_ReturnsNoBrainWhenProviderIsUnknown(t *testing.T) {
+	params := NewMockParams()
+	params.Provider = "unknown"
+	params.Stats = true
+
+	ai, err := mind(*params, facilitatorSystem(), &stats.Stats{})
+
+	require.Error(t, err)
+	assert.Nil(t, ai, "a failed brain must not be wrapped in decorators")
+}
@@ -0,0 +1,265 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cqfn/refrax/internal/domain"
+	"github.com/cqfn/refrax/internal/facilitator"
+	"github.com/cqfn/refrax/internal/log"
+	"github.com/cqfn/refrax/internal/remote"
+	"github.com/cqfn/refrax/internal/stats"
+	"github.com/cqfn/refrax/internal/util"
+)
+
+// agentServer is an agent that can be served over A2A.
+type agentServer interface {
+	shudownable
+	ListenAndServe() error
+	Ready() <-chan bool
+}
+
+// team holds the agents a facilitator works with, along with the local servers
+// that were started for them and their statistics.
+type team struct {
+	critic   domain.Critic
+	fixer    domain.Fixer
+	reviewer domain.Reviewer
+	locals   []agentServer
+	stats    []*stats.Stats
+}
+
+// Start runs a single agent as a standalone A2A server until the context is canceled.
+// Supported agents are critic, fixer, reviewer and facilitator.
+func Start(ctx context.Context, params *Params, agent string) error {
+	initLogger(params)
+	names := []string{agent}
+	if agent == "facilitator" {
+		if err := standalone(params); err != nil {
+			return err
+		}
+		names = params.locals()
+	}
+	p, err := params.limited().authorize(names...)
+	if err != nil {
+		return fmt.Errorf("failed to find token: %w", err)
+	}
+	params = &p
+	s := &stats.Stats{Name: agent}
+	all := []*stats.Stats{s}
+	var server agentServer
+	switch agent {
+	case "critic":
+		server, err = newCritic(*params, params.Port, s)
+	case "fixer":
+		server, err = newFixer(*params, params.Port, s)
+	case "reviewer":
+		server, err = newReviewer(*params, params.Port, s)
+	case "facilitator":
+		var members *team
+		members, err = assemble(*params)
+		defer members.shutdown()
+		all = append(all, members.stats...)
+		if err == nil {
+			var f *facilitator.A2AFacilitator
+			f, err = newFacilitator(*params, params.Port, s, members.critic, members.fixer, members.reviewer)
+			if err == nil {
+				f.Remote()
+				server = f
+			}
+		}
+	default:
+		return fmt.Errorf("unknown agent %q, expected one of: critic, fixer, reviewer, facilitator", agent)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", agent, err)
+	}
+	if err = serve(ctx, agent, server); err != nil {
+		return err
+	}
+	return printStats(*params, all...)
+}
+
+// standalone checks that a standalone facilitator can run its checks. Its clients send it the classes only,
+// without the build files and the resources of their projects, so the checks would have nothing to build.
+func standalone(params *Params) error {
+	if params.ReviewerURL != "" {
+		log.Warn("The reviewer at %s checks the classes without the build files of the clients' projects", params.ReviewerURL)
+		return nil
+	}
+	if len(params.Checks) > 0 {
+		return fmt.Errorf(
+			"a standalone facilitator can't run the checks %q, its clients send the classes without the build files",
+			strings.Join(params.Checks, ", "),
+		)
+	}
+	return nil
+}
+
+// serve starts the server and blocks until the context is canceled or the server fails.
+func serve(ctx context.Context, name string, server agentServer) error {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.ListenAndServe()
+	}()
+	select {
+	case err := <-errs:
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("%s server failed: %w", name, err)
+		}
+		return nil
+	case <-ctx.Done():
+		log.Info("Received a signal to stop, shutting down the %s...", name)
+	}
+	if err := server.Shutdown(); err != nil {
+		return fmt.Errorf("failed to shut down %s: %w", name, err)
+	}
+	if err := <-errs; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("%s server failed: %w", name, err)
+	}
+	return nil
+}
+
+// assemble prepares the critic, fixer and reviewer for a facilitator.
+// Agents with a URL are used remotely, the others are started locally on free ports.
+func assemble(p Params) (*team, error) {
+	res := &team{locals: make([]agentServer, 0), stats: make([]*stats.Stats, 0)}
+	if p.CriticURL != "" {
+		ctc, err := remote.NewCritic(p.CriticURL)
+		if err != nil {
+			return res, err
+		}
+		res.critic = ctc
+	} else {
+		ctc, err := startLocal(res, p, "critic", newCritic)
+		if err != nil {
+			return res, err
+		}
+		res.critic = ctc
+	}
+	if p.FixerURL != "" {
+		fxr, err := remote.NewFixer(p.FixerURL)
+		if err != nil {
+			return res, err
+		}
+		res.fixer = fxr
+	} else {
+		fxr, err := startLocal(res, p, "fixer", newFixer)
+		if err != nil {
+			return res, err
+		}
+		res.fixer = fxr
+	}
+	if p.ReviewerURL != "" {
+		rvwr, err := remote.NewReviewer(p.ReviewerURL)
+		if err != nil {
+			return res, err
+		}
+		res.reviewer = rvwr
+	} else {
+		rvwr, err := startLocal(res, p, "reviewer", newReviewer)
+		if err != nil {
+			return res, err
+		}
+		res.reviewer = rvwr
+	}
+	return res, nil
+}
+
+// startLocal creates an agent on a free port, starts it in the background and registers it in the team.
+func startLocal[T agentServer](
+	t *team, p Params, name string, create func(Params, int, *stats.Stats) (T, error),
+) (T, error) {
+	var empty T
+	port, err := util.FreePort()
+	if err != nil {
+		return empty, fmt.Errorf("failed to find free port for %s: %w", name, err)
+	}
+	s := &stats.Stats{Name: name}
+	server, err := create(p, port, s)
+	if err != nil {
+		return empty, err
+	}
+	log.Info("Starting %s locally on port %d", name, port)
+	if err = launch(name, server); err != nil {
+		return empty, err
+	}
+	t.locals = append(t.locals, server)
+	t.stats = append(t.stats, s)
+	return server, nil
+}
+
+// launch starts the server in the background and waits until it is ready.
+// It fails if the server stops before it gets ready, e.g. when it can't listen on its port.
+func launch(name string, server agentServer) error {
+	errs := make(chan error, 1)
+	go func() {
+		err := server.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("%s server failed: %v", name, err)
+		}
+		errs <- err
+	}()
+	select {
+	case <-server.Ready():
+		return nil
+	case err := <-errs:
+		if err == nil || errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("%s server stopped before it got ready", name)
+		}
+		return fmt.Errorf("failed to start %s server: %w", name, err)
+	}
+}
+
+// shutdown stops all local servers of the team in reverse order of their start.
+func (t *team) shutdown() {
+	for i := len(t.locals) - 1; i >= 0; i-- {
+		shutdown(t.locals[i])
+	}
+}
+
+// facilitate returns a facilitator for the refactoring: either the remote one, or a local one
+// that works with the assembled team. The returned team must be shut down after use.
+func facilitate(p Params) (domain.Facilitator, *team, error) {
+	if p.FacilitatorURL != "" && (p.Commit || p.Branch != "") {
+		return nil, &team{}, fmt.Errorf("refactoring rounds can't be committed by a remote facilitator")
+	}
+	if p.FacilitatorURL != "" {
+		f, err := remote.NewFacilitator(p.FacilitatorURL)
+		if err != nil {
+			return nil, &team{}, err
+		}
+		return f, &team{}, nil
+	}
+	members, err := assemble(p)
+	if err != nil {
+		return nil, members, err
+	}
+	port, err := util.FreePort()
+	if err != nil {
+		return nil, members, fmt.Errorf("failed to find free port for facilitator: %w", err)
+	}
+	s := &stats.Stats{Name: "facilitator"}
+	var f *facilitator.A2AFacilitator
+	f, err = newFacilitator(p, port, s, members.critic, members.fixer, members.reviewer)
+	if err != nil {
+		return nil, members, err
+	}
+	if p.Commit || p.Branch != "" {
+		hook, cerr := committer(p)
+		if cerr != nil {
+			return nil, members, cerr
+		}
+		f.OnRound(hook)
+	}
+	log.Info("Starting facilitator locally on port %d", port)
+	if err = launch("facilitator", f); err != nil {
+		return nil, members, err
+	}
+	members.locals = append(members.locals, f)
+	members.stats = append(members.stats, s)
+	return f, members, nil
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cqfn/refrax/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStart_UnknownAgent(t *testing.T) {
+	err := Start(context.Background(), NewMockParams(), "unknown")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown agent \"unknown\"")
+}
+
+func TestStart_ServesCriticUntilCanceled(t *testing.T) {
+	params := NewMockParams()
+	port, err := util.FreePort()
+	require.NoError(t, err)
+	params.Port = port
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() { done <- Start(ctx, params, "critic") }()
+
+	require.Eventually(t, func() bool {
+		resp, gerr := http.Get(fmt.Sprintf("http://localhost:%d/.well-known/agent-card.json", port))
+		if gerr != nil {
+			return false
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	}, 5*time.Second, 50*time.Millisecond)
+	cancel()
+	select {
+	case err = <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("critic server did not stop after cancellation")
+	}
+}
+
+func TestStart_RefusesChecksOnStandaloneFacilitator(t *testing.T) {
+	params := NewMockParams()
+	params.Checks = []string{"mvn clean test"}
+
+	err := Start(context.Background(), params, "facilitator")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "can't run the checks \"mvn clean test\"")
+}
+
+func TestLaunch_ReturnsErrorWhenServerFailsToListen(t *testing.T) {
+	err := launch("critic", &failing{ready: make(chan bool)})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "address already in use")
+}
+
+type failing struct {
+	ready chan bool
+}
+
+func (f *failing) ListenAndServe() error {
+	return errors.New("address already in use")
+}
+
+func (f *failing) Ready() <-chan bool {
+	return f.ready
+}
+
+func (f *failing) Shutdown() error {
+	return nil
+}
@@ -0,0 +1,172 @@
+// Package config loads the project configuration from the .refrax.yml file.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/cqfn/refrax/internal/client"
+	"github.com/cqfn/refrax/internal/log"
+	"gopkg.in/yaml.v2"
+)
+
+// File is the name of the configuration file in the project root.
+const File = ".refrax.yml"
+
+// Config is the configuration of a project that a team can commit along with the code.
+// Tokens are not part of it, they are secrets and come from flags or the environment.
+type Config struct {
+	Provider    string           `yaml:"ai,omitempty"`
+	Model       string           `yaml:"model,omitempty"`
+	BaseURL     string           `yaml:"base-url,omitempty"`
+	Temperature *float64         `yaml:"temperature,omitempty"`
+	MaxTokens   int              `yaml:"max-tokens,omitempty"`
+	Agents      map[string]Agent `yaml:"agents,omitempty"`
+	Checks      []string         `yaml:"checks,omitempty"`
+	Attempts    int              `yaml:"attempts,omitempty"`
+	MaxSize     int              `yaml:"max-size,omitempty"`
+	Prioritizer string           `yaml:"prioritizer,omitempty"`
+	Weights     string           `yaml:"weights,omitempty"`
+	FixMode     string           `yaml:"fix-mode,omitempty"`
+	TokenLimit  int              `yaml:"token-limit,omitempty"`
+	Examples    []string         `yaml:"examples,omitempty"`
+	Include     []string         `yaml:"include,omitempty"`
+	Exclude     []string         `yaml:"exclude,omitempty"`
+	Constraints []string         `yaml:"constraints,omitempty"`
+	Tools       []string         `yaml:"tools,omitempty"`
+}
+
+// Agent holds the AI settings of a single agent.
+type Agent struct {
+	Provider string `yaml:"ai,omitempty"`
+	Model    string `yaml:"model,omitempty"`
+}
+
+// agents are the names of the agents that can be configured separately.
+var agents = []string{"critic", "fixer", "reviewer", "facilitator"}
+
+// Load reads the configuration file from the project root.
+// A project without the file has an empty configuration.
+func Load(root string) (*Config, error) {
+	path := filepath.Join(root, File)
+	content, err := os.ReadFile(filepath.Clean(path))
+	if errors.Is(err, os.ErrNotExist) {
+		log.Debug("Configuration file %s not found, using flags only", path)
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration file %s: %w", path, err)
+	}
+	var cfg Config
+	if err = yaml.UnmarshalStrict(content, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse configuration file %s: %w", path, err)
+	}
+	for name := range cfg.Agents {
+		if !slices.Contains(agents, name) {
+			return nil, fmt.Errorf("unknown agent %q in configuration file %s, expected one of: %v", name, path, agents)
+		}
+	}
+	log.Debug("Loaded configuration from %s", path)
+	return &cfg, nil
+}
+
+// Apply copies the settings of the configuration into the params.
+// The settings whose flags are changed stay as they are, since flags override the file.
+func (c *Config) Apply(p *client.Params, changed func(flag string) bool) {
+	str(&p.Provider, c.Provider, changed("ai"))
+	str(&p.Model, c.Model, changed("model"))
+	str(&p.BaseURL, c.BaseURL, changed("base-url"))
+	if c.Temperature != nil && !changed("temperature") {
+		p.Temperature = *c.Temperature
+	}
+	num(&p.MaxTokens, c.MaxTokens, changed("max-tokens"))
+	for name, role := range roles(p) {
+		a := c.Agents[name]
+		str(&role.Provider, a.Provider, changed(name+"-ai"))
+		str(&role.Model, a.Model, changed(name+"-model"))
+	}
+	list(&p.Checks, c.Checks, changed("check"))
+	num(&p.Attempts, c.Attempts, changed("attempts"))
+	num(&p.MaxSize, c.MaxSize, changed("max-size"))
+	str(&p.Prioritizer, c.Prioritizer, changed("prioritizer"))
+	str(&p.Weights, c.Weights, changed("weights"))
+	str(&p.FixMode, c.FixMode, changed("fix-mode"))
+	num(&p.TokenLimit, c.TokenLimit, changed("token-limit"))
+	list(&p.Examples, c.Examples, changed("example"))
+	list(&p.Include, c.Include, changed("include"))
+	list(&p.Exclude, c.Exclude, changed("exclude"))
+	list(&p.Constraints, c.Constraints, false)
+	list(&p.Tools, c.Tools, changed("tools"))
+}
+
+// Effective returns the configuration that the params represent, e.g. to show it to the user.
+func Effective(p *client.Params) *Config {
+	cfg := &Config{
+		Provider:    p.Provider,
+		Model:       p.Model,
+		BaseURL:     p.BaseURL,
+		MaxTokens:   p.MaxTokens,
+		Agents:      make(map[string]Agent),
+		Checks:      p.Checks,
+		Attempts:    p.Attempts,
+		MaxSize:     p.MaxSize,
+		Prioritizer: p.Prioritizer,
+		Weights:     p.Weights,
+		FixMode:     p.FixMode,
+		TokenLimit:  p.TokenLimit,
+		Examples:    p.Examples,
+		Include:     p.Include,
+		Exclude:     p.Exclude,
+		Constraints: p.Constraints,
+		Tools:       p.Tools,
+	}
+	if p.Temperature >= 0 {
+		t := p.Temperature
+		cfg.Temperature = &t
+	}
+	for name, role := range roles(p) {
+		if role.Provider != "" || role.Model != "" {
+			cfg.Agents[name] = Agent{Provider: role.Provider, Model: role.Model}
+		}
+	}
+	return cfg
+}
+
+// String renders the configuration in the format of the configuration file.
+func (c *Config) String() string {
+	out, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Sprintf("failed to render configuration: %v", err)
+	}
+	return string(out)
+}
+
+func roles(p *client.Params) map[string]*client.Role {
+	return map[string]*client.Role{
+		"critic":      &p.Critic,
+		"fixer":       &p.Fixer,
+		"reviewer":    &p.Reviewer,
+		"facilitator": &p.Facilitator,
+	}
+}
+
+func str(target *string, value string, changed bool) {
+	if value != "" && !changed {
+		*target = value
+	}
+}
+
+func num(target *int, value int, changed bool) {
+	if value != 0 && !changed {
+		*target = value
+	}
+}
+
+func list(target *[]string, value []string, changed bool) {
+	if len(value) > 0 && !changed {
+		*target = value
+	}
+}
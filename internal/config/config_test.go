@@ -0,0 +1,114 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cqfn/refrax/internal/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const example = `ai: openai
+model: gpt-4o
+agents:
+  critic:
+    ai: ollama
+    model: qwen2.5-coder
+checks:
+  - mvn clean test
+attempts: 5
+max-size: 100
+prioritizer: llm
+weights: severity=2
+include:
+  - src/main/**
+constraints:
+  - You cannot suggest using Lombok
+tools:
+  - aibolit
+`
+
+func TestLoad_ReadsConfigurationFile(t *testing.T) {
+	dir := write(t, example)
+
+	cfg, err := Load(dir)
+
+	require.NoError(t, err)
+	assert.Equal(t, "openai", cfg.Provider)
+	assert.Equal(t, Agent{Provider: "ollama", Model: "qwen2.5-coder"}, cfg.Agents["critic"])
+	assert.Equal(t, []string{"mvn clean test"}, cfg.Checks)
+	assert.Equal(t, 5, cfg.Attempts)
+	assert.Equal(t, []string{"aibolit"}, cfg.Tools)
+}
+
+func TestLoad_ReturnsEmptyConfigurationWithoutFile(t *testing.T) {
+	cfg, err := Load(t.TempDir())
+
+	require.NoError(t, err)
+	assert.Equal(t, &Config{}, cfg)
+}
+
+func TestLoad_FailsOnUnknownSetting(t *testing.T) {
+	_, err := Load(write(t, "provider: openai\n"))
+
+	require.Error(t, err)
+}
+
+func TestLoad_FailsOnUnknownAgent(t *testing.T) {
+	_, err := Load(write(t, "agents:\n  linter:\n    ai: openai\n"))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown agent \"linter\"")
+}
+
+func TestApply_CopiesSettingsIntoParams(t *testing.T) {
+	cfg, err := Load(write(t, example))
+	require.NoError(t, err)
+	params := client.NewMockParams()
+
+	cfg.Apply(params, func(string) bool { return false })
+
+	assert.Equal(t, "openai", params.Provider)
+	assert.Equal(t, "gpt-4o", params.Model)
+	assert.Equal(t, client.Role{Provider: "ollama", Model: "qwen2.5-coder"}, params.Critic)
+	assert.Equal(t, 100, params.MaxSize)
+	assert.Equal(t, "llm", params.Prioritizer)
+	assert.Equal(t, "severity=2", params.Weights)
+	assert.Equal(t, []string{"src/main/**"}, params.Include)
+	assert.Equal(t, []string{"You cannot suggest using Lombok"}, params.Constraints)
+}
+
+func TestApply_KeepsSettingsOfChangedFlags(t *testing.T) {
+	cfg, err := Load(write(t, example))
+	require.NoError(t, err)
+	params := client.NewMockParams()
+	params.Model = "gpt-4.1"
+	params.Critic.Model = "llama3"
+
+	cfg.Apply(params, func(flag string) bool { return flag == "model" || flag == "critic-model" })
+
+	assert.Equal(t, "gpt-4.1", params.Model)
+	assert.Equal(t, "llama3", params.Critic.Model)
+	assert.Equal(t, "ollama", params.Critic.Provider)
+	assert.Equal(t, 5, params.Attempts)
+}
+
+func TestEffective_RendersMergedConfiguration(t *testing.T) {
+	params := client.NewMockParams()
+	params.Fixer = client.Role{Model: "gpt-4o"}
+
+	out := Effective(params).String()
+
+	assert.Contains(t, out, "ai: mock\n")
+	assert.Contains(t, out, "agents:\n  fixer:\n    model: gpt-4o\n")
+	assert.NotContains(t, out, "temperature")
+}
+
+func write(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, File), []byte(content), 0o600))
+	return dir
+}
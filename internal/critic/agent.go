@@ -1,6 +1,7 @@
 package critic
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -17,18 +18,21 @@ type agent struct {
 	tools []tool.Tool
 }
 
-// notFound is the message returned when no suggestions are found.
+// notFound is the message the model used to return when no suggestions are found in plain text answers.
 const notFound = "No suggestions found"
 
 // promptData holds the data to be injected into the prompt template.
 type promptData struct {
-	Code     string
-	Defects  []string
-	NotFound string
+	Code       string
+	Skeleton   string
+	Context    []domain.Class
+	Defects    []string
+	Categories []string
+	Severities []string
 }
 
 // Review sends the provided Java class to the Critic for analysis and returns suggested improvements.
-func (c *agent) Review(job *domain.Job) (*domain.Artifacts, error) {
+func (c *agent) Review(ctx context.Context, job *domain.Job) (*domain.Artifacts, error) {
 	class := job.Classes[0]
 	c.log.Debug("Received class %q for analysis", class.Name())
 	imperfections := tool.NewCombined(c.tools...).Imperfections()
@@ -37,9 +41,12 @@ func (c *agent) Review(job *domain.Job) (*domain.Artifacts, error) {
 		imp = strings.Split(imperfections, "\n")
 	}
 	data := promptData{
-		Code:     class.Content(),
-		Defects:  imp,
-		NotFound: notFound,
+		Code:       numbered(class.Content()),
+		Skeleton:   skeleton(job),
+		Context:    job.Context,
+		Defects:    imp,
+		Categories: categories,
+		Severities: severities,
 	}
 	prompt := prompts.User{
 		Data: data,
@@ -47,12 +54,17 @@ func (c *agent) Review(job *domain.Job) (*domain.Artifacts, error) {
 	}
 	p := prompt.String()
 	c.log.Debug("Rendered prompt for class %s: %s", class.Name(), p)
-	answer, err := c.brain.Ask(p)
+	answer, err := c.brain.Ask(ctx, p)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get answer from brain: %w", err)
 	}
 	c.log.Debug("Received answer from brain for class %s: %s", class.Name(), answer)
-	suggestions := c.associated(parseAnswer(answer), class.Path())
+	critiques, err := parseJSON(answer)
+	if err != nil {
+		c.log.Warn("Answer for class %s is not valid JSON, reading it as plain text: %v", class.Name(), err)
+		critiques = parseAnswer(answer)
+	}
+	suggestions := c.associated(critiques, class)
 	logSuggestions(c.log, suggestions)
 	artifacts := domain.Artifacts{
 		Descr: &domain.Description{
@@ -69,25 +81,37 @@ func logSuggestions(logger log.Logger, suggestions []domain.Suggestion) {
 	}
 }
 
-func parseAnswer(answer string) []string {
-	lines := strings.Split(strings.TrimSpace(answer), "\n")
-	var suggestions []string
-	for _, line := range lines {
-		suggestion := strings.TrimSpace(line)
-		if suggestion != "" {
-			suggestions = append(suggestions, suggestion)
+func (c *agent) associated(critiques []critique, class domain.Class) []domain.Suggestion {
+	lines := strings.Count(class.Content(), "\n") + 1
+	res := make([]domain.Suggestion, 0)
+	for _, cr := range critiques {
+		if strings.EqualFold(strings.TrimSpace(cr.Text), notFound) {
+			continue
 		}
+		valid, err := cr.validate(lines)
+		if err != nil {
+			c.log.Warn("Skipping invalid suggestion for %s: %v", class.Path(), err)
+			continue
+		}
+		res = append(res, valid.suggestion(class.Path()))
 	}
-	return suggestions
+	c.log.Info("Found %d suggestions for %s", len(res), class.Path())
+	return res
 }
 
-func (c *agent) associated(critique []string, class string) []domain.Suggestion {
-	res := make([]domain.Suggestion, 0)
-	for _, c := range critique {
-		if !strings.EqualFold(c, notFound) {
-			res = append(res, *domain.NewSuggestion(c, class))
-		}
+// skeleton returns the members of a large class that the code of the job omits, if it is a chunk of the class.
+func skeleton(job *domain.Job) string {
+	if s, ok := job.Param("skeleton"); ok {
+		return fmt.Sprintf("%v", s)
 	}
-	c.log.Info("Found %d suggestions for %s", len(res), class)
-	return res
+	return ""
+}
+
+// numbered prefixes each line of the code with its number, so that the model can refer to the lines.
+func numbered(code string) string {
+	var res strings.Builder
+	for i, line := range strings.Split(code, "\n") {
+		fmt.Fprintf(&res, "%4d | %s\n", i+1, line)
+	}
+	return strings.TrimSuffix(res.String(), "\n")
 }
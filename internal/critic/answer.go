@@ -0,0 +1,100 @@
+package critic
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cqfn/refrax/internal/domain"
+)
+
+// categories are the kinds of issues the critic asks the model to look for.
+var categories = []string{"comments", "inline", "redundancy", "long-method", "complexity", "other"}
+
+// severities are the allowed severities of issues, from the least to the most important.
+var severities = []string{"low", "medium", "high"}
+
+// critique is a single suggestion as the critic prompt asks the model to return it.
+type critique struct {
+	Text      string `json:"text"`
+	Category  string `json:"category"`
+	Severity  string `json:"severity"`
+	Start     int    `json:"start_line"`
+	End       int    `json:"end_line"`
+	Rationale string `json:"rationale"`
+}
+
+// answer is the JSON object the critic prompt asks the model to return.
+type answer struct {
+	Suggestions *[]critique `json:"suggestions"`
+}
+
+// parseJSON reads the suggestions from the JSON answer of the model.
+// Models tend to wrap JSON into markdown fences or to add a sentence before it,
+// so everything around the outermost braces is ignored.
+func parseJSON(text string) ([]critique, error) {
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("answer has no JSON object")
+	}
+	var res answer
+	if err := json.Unmarshal([]byte(text[start:end+1]), &res); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON answer: %w", err)
+	}
+	if res.Suggestions == nil {
+		return nil, fmt.Errorf("JSON answer has no \"suggestions\" field")
+	}
+	return *res.Suggestions, nil
+}
+
+// parseAnswer reads the suggestions from a plain text answer, one suggestion per line.
+func parseAnswer(answer string) []critique {
+	lines := strings.Split(strings.TrimSpace(answer), "\n")
+	var suggestions []critique
+	for _, line := range lines {
+		suggestion := strings.TrimSpace(line)
+		if suggestion != "" {
+			suggestions = append(suggestions, critique{Text: suggestion})
+		}
+	}
+	return suggestions
+}
+
+// validate checks the suggestion against the class it was made for and fixes what can be fixed:
+// unknown categories become "other", unknown severities are dropped, line ranges outside of the class are dropped.
+// It fails if the suggestion has no text.
+func (c critique) validate(lines int) (critique, error) {
+	c.Text = strings.TrimSpace(c.Text)
+	if c.Text == "" {
+		return c, fmt.Errorf("suggestion has no text")
+	}
+	c.Category = strings.ToLower(strings.TrimSpace(c.Category))
+	if c.Category != "" && !slices.Contains(categories, c.Category) {
+		c.Category = "other"
+	}
+	c.Severity = strings.ToLower(strings.TrimSpace(c.Severity))
+	if !slices.Contains(severities, c.Severity) {
+		c.Severity = ""
+	}
+	if c.End == 0 {
+		c.End = c.Start
+	}
+	if c.Start < 1 || c.End < c.Start || c.End > lines {
+		c.Start, c.End = 0, 0
+	}
+	c.Rationale = strings.TrimSpace(c.Rationale)
+	return c, nil
+}
+
+// suggestion converts the critique into a suggestion for the class.
+func (c critique) suggestion(class string) domain.Suggestion {
+	s := domain.NewSuggestion(c.Text, class)
+	s.Category = c.Category
+	s.Severity = c.Severity
+	s.Start = c.Start
+	s.End = c.End
+	s.Rationale = c.Rationale
+	return *s
+}
@@ -0,0 +1,129 @@
+package critic
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cqfn/refrax/internal/domain"
+	"github.com/cqfn/refrax/internal/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// answering is a brain that always gives the same answer.
+type answering struct {
+	answer string
+}
+
+func (a *answering) Ask(_ context.Context, _ string) (string, error) {
+	return a.answer, nil
+}
+
+func TestParseJSON_ReadsSuggestionsWithAllFields(t *testing.T) {
+	answer := `{"suggestions": [{"text": "Inline variable x", "category": "inline", "severity": "low",
+		"start_line": 3, "end_line": 4, "rationale": "It is used once"}]}`
+
+	critiques, err := parseJSON(answer)
+
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		[]critique{{Text: "Inline variable x", Category: "inline", Severity: "low", Start: 3, End: 4, Rationale: "It is used once"}},
+		critiques,
+	)
+}
+
+func TestParseJSON_IgnoresFencesAndPreamble(t *testing.T) {
+	answer := "Here are my suggestions:\n```json\n{\"suggestions\": [{\"text\": \"Remove comment\"}]}\n```\n"
+
+	critiques, err := parseJSON(answer)
+
+	require.NoError(t, err)
+	assert.Equal(t, []critique{{Text: "Remove comment"}}, critiques)
+}
+
+func TestParseJSON_AcceptsEmptyList(t *testing.T) {
+	critiques, err := parseJSON(`{"suggestions": []}`)
+
+	require.NoError(t, err)
+	assert.Empty(t, critiques)
+}
+
+func TestParseJSON_FailsOnPlainText(t *testing.T) {
+	_, err := parseJSON("Inline variable x\nRemove comment")
+
+	assert.Error(t, err)
+}
+
+func TestParseJSON_FailsWithoutSuggestionsField(t *testing.T) {
+	_, err := parseJSON(`{"issues": []}`)
+
+	assert.Error(t, err)
+}
+
+func TestValidate_DropsLinesOutsideOfClass(t *testing.T) {
+	valid, err := critique{Text: "Split method", Start: 8, End: 12}.validate(10)
+
+	require.NoError(t, err)
+	assert.Zero(t, valid.Start)
+	assert.Zero(t, valid.End)
+}
+
+func TestValidate_NormalizesCategoryAndSeverity(t *testing.T) {
+	valid, err := critique{Text: "Split method", Category: "Naming", Severity: "HIGH", Start: 2}.validate(10)
+
+	require.NoError(t, err)
+	assert.Equal(t, "other", valid.Category)
+	assert.Equal(t, "high", valid.Severity)
+	assert.Equal(t, 2, valid.End)
+}
+
+func TestValidate_RejectsEmptyText(t *testing.T) {
+	_, err := critique{Text: "  ", Category: "inline"}.validate(10)
+
+	assert.Error(t, err)
+}
+
+func TestReview_ReturnsStructuredSuggestions(t *testing.T) {
+	c := &agent{
+		brain: &answering{answer: `{"suggestions": [{"text": "Inline x", "category": "inline", "severity": "medium",
+			"start_line": 2, "end_line": 2, "rationale": "Used once"}, {"text": ""}]}`},
+		log: log.NewMock(),
+	}
+	job := &domain.Job{Classes: []domain.Class{domain.NewInMemoryClass("Foo", "Foo.java", "class Foo {\n  int x = 1;\n}")}}
+
+	artifacts, err := c.Review(context.Background(), job)
+
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		[]domain.Suggestion{{
+			ClassPath: "Foo.java", Text: "Inline x", Category: "inline", Severity: "medium", Start: 2, End: 2, Rationale: "Used once",
+		}},
+		artifacts.Suggestions,
+	)
+}
+
+func TestReview_FallsBackToPlainText(t *testing.T) {
+	c := &agent{brain: &answering{answer: "Inline x\n\nRemove comment\n"}, log: log.NewMock()}
+	job := &domain.Job{Classes: []domain.Class{domain.NewInMemoryClass("Foo", "Foo.java", "class Foo {}")}}
+
+	artifacts, err := c.Review(context.Background(), job)
+
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		[]domain.Suggestion{*domain.NewSuggestion("Inline x", "Foo.java"), *domain.NewSuggestion("Remove comment", "Foo.java")},
+		artifacts.Suggestions,
+	)
+}
+
+func TestReview_SkipsNotFoundAnswer(t *testing.T) {
+	c := &agent{brain: &answering{answer: notFound}, log: log.NewMock()}
+	job := &domain.Job{Classes: []domain.Class{domain.NewInMemoryClass("Foo", "Foo.java", "class Foo {}")}}
+
+	artifacts, err := c.Review(context.Background(), job)
+
+	require.NoError(t, err)
+	assert.Empty(t, artifacts.Suggestions)
+}
@@ -12,6 +12,9 @@ import (
 	"github.com/cqfn/refrax/internal/tool"
 )
 
+// Skill is the ID of the skill the critic agent declares in its agent card.
+const Skill = "critic-java-code"
+
 // Critic represents the main struct responsible for analyzing code critiques.
 type Critic struct {
 	server protocol.Server
@@ -46,11 +49,11 @@ func (c *Critic) ListenAndServe() error {
 }
 
 // Review sends the provided Java class to the Critic for analysis and returns suggested improvements.
-func (c *Critic) Review(job *domain.Job) (*domain.Artifacts, error) {
+func (c *Critic) Review(ctx context.Context, job *domain.Job) (*domain.Artifacts, error) {
 	address := fmt.Sprintf("http://localhost:%d", c.port)
 	c.log.Debug("Asking critic (%s) to lint the class...", address)
 	critic := protocol.NewClient(address)
-	resp, err := critic.SendMessage(job.Marshal())
+	resp, err := critic.SendMessage(ctx, job.Marshal())
 	if err != nil {
 		return nil, fmt.Errorf("failed to send message to critic: %w", err)
 	}
@@ -82,17 +85,17 @@ func (c *Critic) think(ctx context.Context, m *protocol.Message) (*protocol.Mess
 	case <-ctx.Done():
 		return nil, fmt.Errorf("context canceled: %w", ctx.Err())
 	default:
-		return c.thinkLong(m)
+		return c.thinkLong(ctx, m)
 	}
 }
 
-func (c *Critic) thinkLong(m *protocol.Message) (*protocol.Message, error) {
+func (c *Critic) thinkLong(ctx context.Context, m *protocol.Message) (*protocol.Message, error) {
 	c.log.Debug("Received message: #%s", m.MessageID)
 	tsk, err := domain.UnmarshalJob(m)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse task from message: %w", err)
 	}
-	artifacts, err := c.agent.Review(tsk)
+	artifacts, err := c.agent.Review(ctx, tsk)
 	if err != nil {
 		return nil, fmt.Errorf("failed to review the task: %w", err)
 	}
@@ -105,5 +108,5 @@ func agentCard(port int) *protocol.AgentCard {
 		WithDescription("Critic Description").
 		WithURL(fmt.Sprintf("http://localhost:%d", port)).
 		WithVersion("0.0.1").
-		AddSkill("critic-java-code", "Critic Java Code", "Give a reasonable critique on Java code")
+		AddSkill(Skill, "Critic Java Code", "Give a reasonable critique on Java code")
 }
@@ -46,6 +46,9 @@ func (m *mock) Shutdown() error {
 func (m *mock) Handler(_ protocol.Handler) {
 }
 
+func (m *mock) TaskStore(_ protocol.TaskStore) {
+}
+
 func (m *mock) MsgHandler(handler protocol.MsgHandler) {
 	m.handler = handler
 }
@@ -0,0 +1,378 @@
+// Package diff compares texts line by line with the Myers algorithm and renders the differences
+// as hunks of a unified diff. It also measures how many lines were added, removed and changed.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// context is the number of unchanged lines around the changes in a hunk.
+const context = 3
+
+// Kind is the kind of a line in a diff.
+type Kind int
+
+const (
+	// Equal is a line that is the same in both texts.
+	Equal Kind = iota
+	// Removed is a line that is only in the text before.
+	Removed
+	// Added is a line that is only in the text after.
+	Added
+)
+
+// Line is a single line of a diff.
+type Line struct {
+	Kind Kind
+	Text string
+	// NoNewline marks the last line of a text that doesn't end with a newline.
+	NoNewline bool
+}
+
+// Hunk is a group of changes along with the unchanged lines around them.
+// The starts are 1-based line numbers, as in a unified diff.
+type Hunk struct {
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+	Lines    []Line
+}
+
+// Stats is the number of lines added and removed by a diff.
+// A removed line replaced by an added one counts as a changed line too.
+type Stats struct {
+	Added   int
+	Removed int
+	Changed int
+}
+
+// Diff is the line-level difference between two texts.
+type Diff struct {
+	lines []Line
+}
+
+// New compares two texts line by line.
+func New(before, after string) *Diff {
+	return Lines(lines(before), lines(after))
+}
+
+// Lines compares two lists of lines. A line that ends with a newline stands for
+// the last line of a text without a trailing newline.
+func Lines(before, after []string) *Diff {
+	ids := make(map[string]int)
+	a := identify(before, ids)
+	b := identify(after, ids)
+	s := &solver{a: a, b: b, removed: make([]bool, len(a)), added: make([]bool, len(b))}
+	s.compare(0, len(a), 0, len(b))
+	res := make([]Line, 0, max(len(a), len(b)))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && s.removed[i]:
+			res = append(res, line(Removed, before[i]))
+			i++
+		case j < len(b) && s.added[j]:
+			res = append(res, line(Added, after[j]))
+			j++
+		default:
+			res = append(res, line(Equal, before[i]))
+			i++
+			j++
+		}
+	}
+	return &Diff{lines: res}
+}
+
+// Measure counts the added, removed and changed lines between two texts.
+// Unlike New, it ignores the difference between Windows and Unix line endings.
+func Measure(before, after string) Stats {
+	return New(normalized(before), normalized(after)).Stats()
+}
+
+// Unified returns the unified diff of the file, or an empty string if the texts are the same.
+// The name is the path of the file relative to the project root.
+func Unified(name, before, after string) string {
+	return New(before, after).Unified(name)
+}
+
+// Stats counts the lines of the diff.
+func (d *Diff) Stats() Stats {
+	res := Stats{}
+	removed, added := 0, 0
+	flush := func() {
+		res.Changed += min(removed, added)
+		removed, added = 0, 0
+	}
+	for _, l := range d.lines {
+		switch l.Kind {
+		case Removed:
+			res.Removed++
+			removed++
+		case Added:
+			res.Added++
+			added++
+		default:
+			flush()
+		}
+	}
+	flush()
+	return res
+}
+
+// Hunks groups the changes with the unchanged lines around them.
+// Changes that are close to each other share a hunk.
+func (d *Diff) Hunks() []Hunk {
+	res := make([]Hunk, 0)
+	oldLine, newLine := 1, 1
+	start := 0
+	for start < len(d.lines) {
+		first := d.next(start)
+		if first < 0 {
+			break
+		}
+		from := max(first-context, start)
+		last := first
+		for i := first; i < len(d.lines) && i-last <= 2*context; i++ {
+			if d.lines[i].Kind != Equal {
+				last = i
+			}
+		}
+		to := min(last+context, len(d.lines)-1)
+		for i := start; i < from; i++ {
+			oldLine++
+			newLine++
+		}
+		h := Hunk{OldStart: oldLine, NewStart: newLine, Lines: d.lines[from : to+1]}
+		for _, l := range h.Lines {
+			if l.Kind != Added {
+				h.OldLines++
+			}
+			if l.Kind != Removed {
+				h.NewLines++
+			}
+		}
+		oldLine += h.OldLines
+		newLine += h.NewLines
+		if h.OldLines == 0 {
+			h.OldStart--
+		}
+		if h.NewLines == 0 {
+			h.NewStart--
+		}
+		res = append(res, h)
+		start = to + 1
+	}
+	return res
+}
+
+// Unified renders the diff of the file in the unified format, or returns an empty string if there are no changes.
+func (d *Diff) Unified(name string) string {
+	hunks := d.Hunks()
+	if len(hunks) == 0 {
+		return ""
+	}
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- a/%s\n+++ b/%s\n", name, name)
+	for _, h := range hunks {
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+		for _, l := range h.Lines {
+			switch l.Kind {
+			case Equal:
+				out.WriteString(" ")
+			case Removed:
+				out.WriteString("-")
+			case Added:
+				out.WriteString("+")
+			}
+			out.WriteString(l.Text)
+			out.WriteString("\n")
+			if l.NoNewline {
+				out.WriteString("\\ No newline at end of file\n")
+			}
+		}
+	}
+	return out.String()
+}
+
+// String returns a short summary of the stats, e.g. "+2 -1 ~1".
+func (s Stats) String() string {
+	return fmt.Sprintf("+%d -%d ~%d", s.Added, s.Removed, s.Changed)
+}
+
+// Size is the number of touched lines, where a changed line counts once.
+func (s Stats) Size() int {
+	return s.Added + s.Removed - s.Changed
+}
+
+// next returns the position of the first change at or after the start, or -1 if there is none.
+func (d *Diff) next(start int) int {
+	for i := start; i < len(d.lines); i++ {
+		if d.lines[i].Kind != Equal {
+			return i
+		}
+	}
+	return -1
+}
+
+// solver finds the shortest edit script with the linear space variant of the Myers algorithm:
+// it splits both texts at the middle snake of the edit graph and solves the halves recursively.
+type solver struct {
+	a       []int
+	b       []int
+	removed []bool
+	added   []bool
+}
+
+// compare marks the removed and added lines between the given bounds.
+func (s *solver) compare(alo, ahi, blo, bhi int) {
+	for alo < ahi && blo < bhi && s.a[alo] == s.b[blo] {
+		alo++
+		blo++
+	}
+	for alo < ahi && blo < bhi && s.a[ahi-1] == s.b[bhi-1] {
+		ahi--
+		bhi--
+	}
+	if alo == ahi || blo == bhi {
+		s.replace(alo, ahi, blo, bhi)
+		return
+	}
+	x, y := middle(s.a[alo:ahi], s.b[blo:bhi])
+	if x < 0 || (x == 0 && y == 0) || (x == ahi-alo && y == bhi-blo) {
+		s.replace(alo, ahi, blo, bhi)
+		return
+	}
+	s.compare(alo, alo+x, blo, blo+y)
+	s.compare(alo+x, ahi, blo+y, bhi)
+}
+
+// replace marks all the lines between the bounds as removed and added.
+func (s *solver) replace(alo, ahi, blo, bhi int) {
+	for i := alo; i < ahi; i++ {
+		s.removed[i] = true
+	}
+	for j := blo; j < bhi; j++ {
+		s.added[j] = true
+	}
+}
+
+// middle finds the point where the forward and the reverse searches of the edit graph meet.
+// It returns -1 if they don't meet, which only happens when the texts have nothing in common.
+func middle(a, b []int) (int, int) {
+	n, m := len(a), len(b)
+	limit := (n + m + 1) / 2
+	offset := limit + 1
+	forward := make([]int, 2*offset+1)
+	reverse := make([]int, 2*offset+1)
+	for i := range forward {
+		forward[i] = -1
+		reverse[i] = -1
+	}
+	forward[offset+1] = 0
+	reverse[offset+1] = 0
+	delta := n - m
+	odd := delta%2 != 0
+	fstart, fend, rstart, rend := 0, 0, 0, 0
+	for d := 0; d < limit; d++ {
+		for k := -d + fstart; k <= d-fend; k += 2 {
+			i := offset + k
+			var x int
+			if k == -d || (k != d && forward[i-1] < forward[i+1]) {
+				x = forward[i+1]
+			} else {
+				x = forward[i-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			forward[i] = x
+			switch {
+			case x > n:
+				fend += 2
+			case y > m:
+				fstart += 2
+			case odd:
+				r := offset + delta - k
+				if r >= 0 && r < len(reverse) && reverse[r] != -1 && x >= n-reverse[r] {
+					return x, y
+				}
+			}
+		}
+		for k := -d + rstart; k <= d-rend; k += 2 {
+			i := offset + k
+			var x int
+			if k == -d || (k != d && reverse[i-1] < reverse[i+1]) {
+				x = reverse[i+1]
+			} else {
+				x = reverse[i-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[n-x-1] == b[m-y-1] {
+				x++
+				y++
+			}
+			reverse[i] = x
+			switch {
+			case x > n:
+				rend += 2
+			case y > m:
+				rstart += 2
+			case !odd:
+				f := offset + delta - k
+				if f >= 0 && f < len(forward) && forward[f] != -1 {
+					fx := forward[f]
+					fy := fx - (f - offset)
+					if fx >= n-x {
+						return fx, fy
+					}
+				}
+			}
+		}
+	}
+	return -1, -1
+}
+
+// identify replaces the lines with numbers, so that the algorithm compares numbers instead of strings.
+func identify(lines []string, ids map[string]int) []int {
+	res := make([]int, len(lines))
+	for i, l := range lines {
+		id, ok := ids[l]
+		if !ok {
+			id = len(ids)
+			ids[l] = id
+		}
+		res[i] = id
+	}
+	return res
+}
+
+// line creates a line of the diff from the line of a text, possibly marked with a trailing newline.
+func line(kind Kind, text string) Line {
+	if strings.HasSuffix(text, "\n") {
+		return Line{Kind: kind, Text: strings.TrimSuffix(text, "\n"), NoNewline: true}
+	}
+	return Line{Kind: kind, Text: text}
+}
+
+// lines splits the text into lines. The last line of a text without a trailing newline keeps
+// the newline as a mark, so it differs from the same line followed by a newline.
+func lines(text string) []string {
+	if text == "" {
+		return []string{}
+	}
+	res := strings.Split(text, "\n")
+	if res[len(res)-1] == "" {
+		return res[:len(res)-1]
+	}
+	res[len(res)-1] += "\n"
+	return res
+}
+
+// normalized replaces Windows line endings with Unix ones.
+func normalized(text string) string {
+	return strings.ReplaceAll(text, "\r\n", "\n")
+}
@@ -0,0 +1,253 @@
+package diff
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnified_ReturnsNothingForSameTexts(t *testing.T) {
+	assert.Empty(t, Unified("Foo.java", "class Foo {}\n", "class Foo {}\n"))
+}
+
+func TestUnified_RendersChangedLine(t *testing.T) {
+	before := "a\nb\nc\nd\ne\nf\ng\nh\n"
+	after := "a\nb\nc\nd\nE\nf\ng\nh\n"
+
+	patch := Unified("src/Foo.java", before, after)
+
+	assert.Equal(
+		t,
+		"--- a/src/Foo.java\n+++ b/src/Foo.java\n@@ -2,7 +2,7 @@\n b\n c\n d\n-e\n+E\n f\n g\n h\n",
+		patch,
+	)
+}
+
+func TestUnified_SplitsDistantChangesIntoHunks(t *testing.T) {
+	before := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	after := "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ntwelve\n"
+
+	patch := Unified("Foo.java", before, after)
+
+	assert.Equal(
+		t,
+		"--- a/Foo.java\n+++ b/Foo.java\n@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+twelve\n",
+		patch,
+	)
+}
+
+func TestUnified_MarksMissingNewlineAtEndOfFile(t *testing.T) {
+	patch := Unified("Foo.java", "class Foo {}\n", "class Foo {}")
+
+	assert.Equal(
+		t,
+		"--- a/Foo.java\n+++ b/Foo.java\n@@ -1,1 +1,1 @@\n-class Foo {}\n+class Foo {}\n\\ No newline at end of file\n",
+		patch,
+	)
+}
+
+func TestUnified_RendersNewFileContent(t *testing.T) {
+	patch := Unified("Foo.java", "", "class Foo {}\n")
+
+	assert.Equal(t, "--- a/Foo.java\n+++ b/Foo.java\n@@ -0,0 +1,1 @@\n+class Foo {}\n", patch)
+}
+
+func TestUnified_ProducesPatchThatGitApplies(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	before := "package a;\n\nclass Foo {\n  int x;\n  int y;\n\n  void f() {\n    x = 1;\n  }\n}\n"
+	after := "package a;\n\nfinal class Foo {\n  private int x;\n  int y;\n\n  void f() {\n    this.x = 1;\n  }\n}"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Foo.java"), []byte(before), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "out.diff"), []byte(Unified("Foo.java", before, after)), 0o600))
+	cmd := exec.Command("git", "apply", "out.diff")
+	cmd.Dir = dir
+
+	out, err := cmd.CombinedOutput()
+
+	require.NoError(t, err, string(out))
+	applied, err := os.ReadFile(filepath.Join(dir, "Foo.java"))
+	require.NoError(t, err)
+	assert.Equal(t, after, string(applied))
+}
+
+func TestStats_CountsAddedRemovedAndChangedLines(t *testing.T) {
+	before := "a\nb\nc\nd\n"
+	after := "a\nB\nc\nd\ne\nf\n"
+
+	stats := New(before, after).Stats()
+
+	assert.Equal(t, Stats{Added: 3, Removed: 1, Changed: 1}, stats)
+	assert.Equal(t, 3, stats.Size())
+	assert.Equal(t, "+3 -1 ~1", stats.String())
+}
+
+func TestMeasure_IgnoresWindowsLineEndings(t *testing.T) {
+	before := "class Foo {\r\n  int x;\r\n}\r\n"
+	after := "class Foo {\n  private int x;\n}\n"
+
+	assert.Equal(t, Stats{Added: 1, Removed: 1, Changed: 1}, Measure(before, after))
+}
+
+func TestHunks_ReportsLinePositions(t *testing.T) {
+	before := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+	after := "1\n2\n3\n4\n5\n6\n7\n8\nnine\n10\n"
+
+	hunks := New(before, after).Hunks()
+
+	require.Len(t, hunks, 1)
+	assert.Equal(t, 6, hunks[0].OldStart)
+	assert.Equal(t, 5, hunks[0].OldLines)
+	assert.Equal(t, 6, hunks[0].NewStart)
+	assert.Equal(t, 5, hunks[0].NewLines)
+	assert.Equal(t, Line{Kind: Removed, Text: "9"}, hunks[0].Lines[3])
+	assert.Equal(t, Line{Kind: Added, Text: "nine"}, hunks[0].Lines[4])
+}
+
+func TestLines_FindsShortestEditScript(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(42, 7))
+	for range 200 {
+		before := random(rnd, rnd.IntN(30))
+		after := random(rnd, rnd.IntN(30))
+
+		stats := Lines(before, after).Stats()
+
+		common := lcs(before, after)
+		require.Equal(t, len(before)-common, stats.Removed, "before %v, after %v", before, after)
+		require.Equal(t, len(after)-common, stats.Added, "before %v, after %v", before, after)
+	}
+}
+
+func TestLines_KeepsBothTextsInOrder(t *testing.T) {
+	before := []string{"a", "b", "c", "a", "b", "b", "a"}
+	after := []string{"c", "b", "a", "b", "a", "c"}
+
+	var restored, result []string
+	for _, l := range Lines(before, after).lines {
+		if l.Kind != Added {
+			restored = append(restored, l.Text)
+		}
+		if l.Kind != Removed {
+			result = append(result, l.Text)
+		}
+	}
+
+	assert.Equal(t, before, restored)
+	assert.Equal(t, after, result)
+}
+
+func TestNew_HandlesLargeClassesQuickly(t *testing.T) {
+	var before, after strings.Builder
+	for i := range 50_000 {
+		fmt.Fprintf(&before, "  int field%d = %d;\n", i, i)
+		if i%100 == 0 {
+			fmt.Fprintf(&after, "  private int field%d = %d;\n", i, i)
+		} else {
+			fmt.Fprintf(&after, "  int field%d = %d;\n", i, i)
+		}
+	}
+	start := time.Now()
+
+	stats := New(before.String(), after.String()).Stats()
+
+	assert.Equal(t, Stats{Added: 500, Removed: 500, Changed: 500}, stats)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func random(rnd *rand.Rand, n int) []string {
+	res := make([]string, n)
+	for i := range res {
+		res[i] = string(rune('a' + rnd.IntN(3)))
+	}
+	return res
+}
+
+func lcs(a, b []string) int {
+	table := make([][]int, len(a)+1)
+	for i := range table {
+		table[i] = make([]int, len(b)+1)
+	}
+	for i := range a {
+		for j := range b {
+			if a[i] == b[j] {
+				table[i+1][j+1] = table[i][j] + 1
+			} else {
+				table[i+1][j+1] = max(table[i][j+1], table[i+1][j])
+			}
+		}
+	}
+	return table[len(a)][len(b)]
+}
+
+func TestMeasure_CountsLineInsertedInMiddle(t *testing.T) {
+	assert.Equal(t, Stats{Added: 1}, Measure("ab\nb\nd\ne\n", "ab\nb\nc\nd\ne\n"))
+}
+
+func TestMeasure_CountsLineDeletedInMiddle(t *testing.T) {
+	assert.Equal(t, Stats{Removed: 1}, Measure("ad\nb\nc\nd\ne\n", "ad\nb\nd\ne\n"))
+}
+
+func TestMeasure_CountsChangesAfterCommonPrefix(t *testing.T) {
+	assert.Equal(t, Stats{Added: 2, Removed: 2, Changed: 2}, Measure("ah\nb\nx\ny\n", "ah\nb\nc\nd\n"))
+}
+
+func TestMeasure_CountsChangesBeforeCommonSuffix(t *testing.T) {
+	assert.Equal(t, Stats{Added: 2, Removed: 2, Changed: 2}, Measure("x\ny\na\nb\n", "z\nw\na\nb\n"))
+}
+
+func TestMeasure_CountsInterleavedDeletions(t *testing.T) {
+	assert.Equal(t, Stats{Removed: 2}, Measure("ai\nx\nb\ny\nc\n", "ai\nb\nc\n"))
+}
+
+func TestMeasure_CountsMovedLinesAsRemovedAndAdded(t *testing.T) {
+	stats := Measure("a\nb\nc\nd\n", "c\na\nd\nb\n")
+
+	assert.Equal(t, 2, stats.Added, "Only the lines in the same order are matched")
+	assert.Equal(t, 2, stats.Removed, "Only the lines in the same order are matched")
+}
+
+func TestMeasure_CountsOneLineChangedOutOfMany(t *testing.T) {
+	assert.Equal(t, Stats{Added: 1, Removed: 1, Changed: 1}, Measure("a\nb\nc\nd\ne\n", "a\nb\nX\nd\ne\n"))
+}
+
+func TestMeasure_CountsAllLinesChanged(t *testing.T) {
+	assert.Equal(t, Stats{Added: 3, Removed: 3, Changed: 3}, Measure("a\nb\nc\n", "x\ny\nz\n"))
+}
+
+func TestMeasure_CountsLineAddedToEmptyText(t *testing.T) {
+	assert.Equal(t, Stats{Added: 1}, Measure("", "hello\n"))
+}
+
+func TestMeasure_CountsOnlyLineRemoved(t *testing.T) {
+	assert.Equal(t, Stats{Removed: 1}, Measure("bye\n", ""))
+}
+
+func TestMeasure_CountsRealisticCodeChange(t *testing.T) {
+	before := "func add(a, b int) int {\n    return a + b\n}\n"
+	after := "func add(a, b int) int {\n    result := a + b\n    return result\n}\n"
+
+	stats := Measure(before, after)
+
+	assert.Equal(t, Stats{Added: 2, Removed: 1, Changed: 1}, stats)
+	assert.Equal(t, 2, stats.Size(), "One line was changed and one was added")
+}
+
+func TestMeasure_CountsChangesOfJavaClass(t *testing.T) {
+	before, err := os.ReadFile(filepath.Join("test_data", "Before.java"))
+	require.NoError(t, err)
+	after, err := os.ReadFile(filepath.Join("test_data", "After.java"))
+	require.NoError(t, err)
+
+	stats := Measure(string(before), string(after))
+
+	assert.Equal(t, Stats{Removed: 7}, stats)
+}
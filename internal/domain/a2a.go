@@ -2,6 +2,7 @@ package domain
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/cqfn/refrax/internal/protocol"
 	"github.com/cqfn/refrax/internal/util"
@@ -12,6 +13,7 @@ const (
 	typeClass      = "class"
 	typeSuggestion = "suggestion"
 	typeExample    = "example"
+	typeContext    = "context"
 )
 
 func UnmarshalArtifacts(msg *protocol.Message) (*Artifacts, error) {
@@ -63,6 +65,7 @@ func UnmarshalJob(msg *protocol.Message) (*Job, error) {
 	job.Descr = descr
 	classes := make([]Class, 0)
 	examples := make([]Class, 0)
+	context := make([]Class, 0)
 	suggestions := make([]Suggestion, 0)
 	for _, part := range msg.Parts[1:] {
 		metas := part.Metadata()
@@ -80,6 +83,12 @@ func UnmarshalJob(msg *protocol.Message) (*Job, error) {
 				return nil, fmt.Errorf("failed to unmarshal example class: %w", err)
 			}
 			examples = append(examples, e)
+		case typeContext:
+			c, err := UnmarshalClass(part)
+			if err != nil {
+				return nil, fmt.Errorf("failed to unmarshal context class: %w", err)
+			}
+			context = append(context, c)
 		case typeSuggestion:
 			s, err := UnmarshalSuggestion(part)
 			if err != nil {
@@ -92,6 +101,7 @@ func UnmarshalJob(msg *protocol.Message) (*Job, error) {
 	}
 	job.Classes = classes
 	job.Examples = examples
+	job.Context = context
 	job.Suggestions = suggestions
 	return job, nil
 }
@@ -112,9 +122,15 @@ func UnmarshalSuggestion(part protocol.Part) (*Suggestion, error) {
 		if !ok {
 			return nil, fmt.Errorf("missing or invalid class-path metadata in suggestion part")
 		}
+		meta := tp.Metadata()
 		suggestion := &Suggestion{
 			ClassPath: path,
 			Text:      text,
+			Category:  optional(meta, "category"),
+			Severity:  optional(meta, "severity"),
+			Start:     number(meta, "start-line"),
+			End:       number(meta, "end-line"),
+			Rationale: optional(meta, "rationale"),
 		}
 		return suggestion, nil
 	}
@@ -176,6 +192,9 @@ func (j *Job) Marshal() *protocol.MessageSendParams {
 			msg.AddPart(MarshalClass(example, typeExample))
 		}
 	}
+	for _, related := range j.Context {
+		msg.AddPart(MarshalClass(related, typeContext))
+	}
 	return protocol.NewMessageSendParams().WithMessage(msg)
 }
 
@@ -212,9 +231,49 @@ func (s *Suggestion) Marshal() protocol.Part {
 	part := protocol.NewText(s.Text).
 		WithMetadata("class-path", s.ClassPath).
 		WithMetadata("type", typeSuggestion)
+	if s.Category != "" {
+		part = part.WithMetadata("category", s.Category)
+	}
+	if s.Severity != "" {
+		part = part.WithMetadata("severity", s.Severity)
+	}
+	if s.Start > 0 {
+		part = part.WithMetadata("start-line", s.Start).WithMetadata("end-line", s.End)
+	}
+	if s.Rationale != "" {
+		part = part.WithMetadata("rationale", s.Rationale)
+	}
 	return part
 }
 
+// optional returns the string metadata value, or an empty string if there is none.
+func optional(meta map[string]any, key string) string {
+	v, ok := meta[key]
+	if !ok || v == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// number returns the integer metadata value, or zero if there is none.
+// JSON decodes numbers as float64, so both kinds are accepted.
+func number(meta map[string]any, key string) int {
+	switch v := meta[key].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	case string:
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return 0
+		}
+		return n
+	default:
+		return 0
+	}
+}
+
 func MarshalClass(c Class, t string) protocol.Part {
 	return protocol.NewFileBytes([]byte(c.Content())).
 		WithMetadata("type", t).
@@ -3,6 +3,7 @@ package domain
 import (
 	"testing"
 
+	"github.com/cqfn/refrax/internal/protocol"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -25,8 +26,44 @@ func TestMarshalAndUnmarshalJob(t *testing.T) {
 			*NewSuggestion("Add documentation to AnotherClass", "test/path/AnotherClass.java"),
 			*NewSuggestion("Refactor ExampleClass", "test/path/ExampleClass.java"),
 		},
+		Context: []Class{
+			NewInMemoryClass("Base", "test/path/Base.java", "public abstract class Base\n  public int size()"),
+		},
 	}
 	after, err := UnmarshalJob(before.Marshal().Message)
 	require.NoError(t, err, "Unmarshaling job should not return an error")
 	assert.Equal(t, before, after, "Jobs should be equal after marshaling and unmarshaling")
 }
+
+func TestMarshalAndUnmarshalStructuredSuggestion(t *testing.T) {
+	before := &Artifacts{
+		Descr: &Description{Text: "critique"},
+		Suggestions: []Suggestion{{
+			ClassPath: "Foo.java",
+			Text:      "Inline variable x",
+			Category:  "inline",
+			Severity:  "low",
+			Start:     3,
+			End:       4,
+			Rationale: "It is used once",
+		}},
+	}
+
+	after, err := UnmarshalArtifacts(before.Marshal().Message)
+
+	require.NoError(t, err)
+	assert.Equal(t, before.Suggestions, after.Suggestions)
+}
+
+func TestUnmarshalSuggestion_ReadsLinesDecodedFromJSON(t *testing.T) {
+	part := protocol.NewText("Inline variable x").
+		WithMetadata("class-path", "Foo.java").
+		WithMetadata("start-line", float64(3)).
+		WithMetadata("end-line", float64(4))
+
+	s, err := UnmarshalSuggestion(part)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, s.Start)
+	assert.Equal(t, 4, s.End)
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 )
@@ -11,18 +12,21 @@ type Facilitator interface {
 }
 
 // Critic represents an interface to review a class and provide suggestions.
+// The review stops as soon as the context is canceled.
 type Critic interface {
-	Review(job *Job) (*Artifacts, error)
+	Review(ctx context.Context, job *Job) (*Artifacts, error)
 }
 
 // Fixer represents an interface to fix a class based on suggestions and an example.
+// The fix stops as soon as the context is canceled.
 type Fixer interface {
-	Fix(job *Job) (*Artifacts, error)
+	Fix(ctx context.Context, job *Job) (*Artifacts, error)
 }
 
 // Reviewer represents an interface for a reviewer that can review changes made.
+// The "dir" parameter of the job is the directory to run the checks in, the current one by default.
 type Reviewer interface {
-	Review() (*Artifacts, error)
+	Review(ctx context.Context, job *Job) (*Artifacts, error)
 }
 
 type Job struct {
@@ -30,6 +34,9 @@ type Job struct {
 	Classes     []Class
 	Suggestions []Suggestion
 	Examples    []Class
+	// Context holds the outlines of the classes related to the ones of the job, e.g. their supertypes and callers.
+	// An outline lists the declarations of a class rather than its whole code.
+	Context []Class
 }
 
 func (j *Job) Param(key string) (any, bool) {
@@ -49,6 +56,35 @@ func (j *Job) MaxSize() (int, error) {
 	return strconv.Atoi(ssize)
 }
 
+// TokenLimit returns the number of tokens of a class the agents can take at once, 6000 by default.
+// Larger classes are split into chunks of members that fit into the limit.
+func (j *Job) TokenLimit() (int, error) {
+	limit, ok := j.Param("token-limit")
+	if !ok {
+		return 6_000, nil
+	}
+	return strconv.Atoi(fmt.Sprintf("%v", limit))
+}
+
+// Dir returns the directory the job works in, or an empty string for the current one.
+func (j *Job) Dir() string {
+	dir, ok := j.Param("dir")
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%v", dir)
+}
+
+// FixMode returns how the fixer should return the fixed class: "full" for the whole file, the default,
+// or "edits" for search/replace blocks applied to the original content.
+func (j *Job) FixMode() string {
+	mode, ok := j.Param("fix-mode")
+	if !ok || fmt.Sprintf("%v", mode) == "" {
+		return "full"
+	}
+	return fmt.Sprintf("%v", mode)
+}
+
 type Artifacts struct {
 	Descr       *Description
 	Classes     []Class
@@ -60,11 +96,22 @@ type Description struct {
 	Meta map[string]any
 }
 
+// Suggestion is an improvement proposed for a class.
+// The category, the severity, the line range and the rationale are optional,
+// the critic fills them in when the model returns a structured answer.
 type Suggestion struct {
 	ClassPath string
 	Text      string
+	Category  string
+	Severity  string
+	Start     int
+	End       int
+	Rationale string
 }
 
 func (s *Suggestion) String() string {
+	if s.Start > 0 {
+		return fmt.Sprintf("suggestion for %s (lines %d-%d): %s", s.ClassPath, s.Start, s.End, s.Text)
+	}
 	return fmt.Sprintf("suggestion for %s: %s", s.ClassPath, s.Text)
 }
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// ChangedProject decorates a project and keeps only the classes from the given list of changed files.
+type ChangedProject struct {
+	origin Project
+	root   string
+	files  map[string]bool
+}
+
+// NewChangedProject creates a project with the classes of the origin that are in the list of changed files.
+// The files are relative to the root of the origin project.
+func NewChangedProject(origin Project, root string, files ...string) *ChangedProject {
+	set := make(map[string]bool, len(files))
+	for _, f := range files {
+		set[filepath.ToSlash(filepath.Clean(f))] = true
+	}
+	return &ChangedProject{origin: origin, root: root, files: set}
+}
+
+// Classes retrieves the Java classes of the origin project that were changed.
+func (p *ChangedProject) Classes() ([]Class, error) {
+	all, err := p.origin.Classes()
+	if err != nil {
+		return nil, err
+	}
+	root, err := filepath.Abs(p.root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project root %s: %w", p.root, err)
+	}
+	res := make([]Class, 0)
+	for _, c := range all {
+		abs, aerr := filepath.Abs(c.Path())
+		if aerr != nil {
+			return nil, fmt.Errorf("failed to resolve path of class %s: %w", c.Name(), aerr)
+		}
+		rel, rerr := filepath.Rel(root, abs)
+		if rerr != nil {
+			continue
+		}
+		if p.files[filepath.ToSlash(rel)] {
+			res = append(res, c)
+		}
+	}
+	return res, nil
+}
+
+// String returns the string representation of the ChangedProject.
+func (p *ChangedProject) String() string {
+	return fmt.Sprintf("%v (%d changed files)", p.origin, len(p.files))
+}
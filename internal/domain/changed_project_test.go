@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedProject_Classes_KeepsChangedClassesOnly(t *testing.T) {
+	tmp := t.TempDir()
+	write(t, tmp, "src/Foo.java", "class Foo {}")
+	write(t, tmp, "src/Bar.java", "class Bar {}")
+	project := NewChangedProject(NewFilesystem(tmp), tmp, "src/Foo.java", "README.md")
+
+	classes, err := project.Classes()
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Foo"}, names(classes))
+}
+
+func TestChangedProject_Classes_ReturnsNothingWithoutChanges(t *testing.T) {
+	tmp := t.TempDir()
+	write(t, tmp, "Foo.java", "class Foo {}")
+
+	classes, err := NewChangedProject(NewFilesystem(tmp), tmp).Classes()
+
+	require.NoError(t, err)
+	assert.Empty(t, classes)
+}
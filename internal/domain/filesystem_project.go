@@ -2,14 +2,30 @@ package domain
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 )
 
+// builds are the directories with build output and tool caches, they are never refactored.
+// Inside of a source directory these names are rather Java packages, so they are kept there.
+var builds = []string{
+	".git", ".gradle", ".idea", ".mvn", "bin", "build", "generated-sources",
+	"generated-test-sources", "node_modules", "out", "target",
+}
+
+// generated finds the marks of generated code in the header of a file, e.g. the @Generated annotation
+// or a "DO NOT EDIT" comment.
+var generated = regexp.MustCompile(`@(?:[\w.]+\.)?Generated\b|DO NOT EDIT`)
+
 // FSProj represents a project stored in the filesystem.
 type FSProj struct {
-	path string
+	path    string
+	include []string
+	exclude []string
 }
 
 // NewFilesystem creates a new FilesystemProject with the given path.
@@ -17,18 +33,51 @@ func NewFilesystem(path string) *FSProj {
 	return &FSProj{path: path}
 }
 
+// WithInclude limits the classes of the project to the ones that match any of the glob patterns.
+// The patterns are relative to the project root and support "**", e.g. "src/main/**".
+func (p *FSProj) WithInclude(patterns ...string) *FSProj {
+	p.include = append(p.include, patterns...)
+	return p
+}
+
+// WithExclude skips the classes that match any of the glob patterns, e.g. "src/test/**".
+func (p *FSProj) WithExclude(patterns ...string) *FSProj {
+	p.exclude = append(p.exclude, patterns...)
+	return p
+}
+
 // Classes retrieves all Java classes in the project directory and its subdirectories.
+// Build output directories, files ignored by .gitignore and generated code are skipped.
 func (p *FSProj) Classes() ([]Class, error) {
 	var classes []Class
-	err := filepath.Walk(p.path, func(path string, info os.FileInfo, err error) error {
+	ignore := &gitignore{}
+	err := filepath.WalkDir(p.path, func(path string, entry fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
+		rel, err := filepath.Rel(p.path, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if entry.IsDir() {
+			if rel != "." && (isBuild(rel) || ignore.ignored(rel, true)) {
+				return filepath.SkipDir
+			}
+			return ignore.load(p.path, rel)
+		}
 		ext := ".java"
-		if !info.IsDir() && strings.HasSuffix(info.Name(), ext) {
-			c := NewFSClass(strings.TrimSuffix(info.Name(), ext), path)
-			classes = append(classes, c)
+		if !strings.HasSuffix(entry.Name(), ext) || ignore.ignored(rel, false) {
+			return nil
+		}
+		ok, err := p.selected(rel)
+		if err != nil || !ok {
+			return err
 		}
+		if gen, gerr := isGenerated(path); gerr != nil || gen {
+			return gerr
+		}
+		classes = append(classes, NewFSClass(strings.TrimSuffix(entry.Name(), ext), path))
 		return nil
 	})
 	if err != nil {
@@ -41,3 +90,80 @@ func (p *FSProj) Classes() ([]Class, error) {
 func (p *FSProj) String() string {
 	return fmt.Sprintf("[%s]", p.path)
 }
+
+// at returns the same project with the same filters located at another path.
+func (p *FSProj) at(path string) *FSProj {
+	return &FSProj{path: path, include: p.include, exclude: p.exclude}
+}
+
+// selected reports whether the file passes the include and exclude patterns.
+func (p *FSProj) selected(rel string) (bool, error) {
+	if len(p.include) > 0 {
+		ok, err := matchesAny(p.include, rel)
+		if err != nil {
+			return false, fmt.Errorf("invalid include pattern: %w", err)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	ok, err := matchesAny(p.exclude, rel)
+	if err != nil {
+		return false, fmt.Errorf("invalid exclude pattern: %w", err)
+	}
+	return !ok, nil
+}
+
+// isBuild reports whether the directory holds build output rather than sources.
+func isBuild(rel string) bool {
+	parts := strings.Split(rel, "/")
+	return slices.Contains(builds, parts[len(parts)-1]) && !slices.Contains(parts[:len(parts)-1], "src")
+}
+
+// isGenerated reports whether the file is marked as generated code that nobody maintains by hand.
+// Only the header of the file is inspected, so the marks inside of the types, e.g. on their members,
+// in their comments or in their string literals, don't count.
+func isGenerated(path string) (bool, error) {
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return generated.MatchString(header(string(content))), nil
+}
+
+// header returns the text of the Java file before the body of its first type: the header comment,
+// the package, the imports and the declaration of the type with its annotations.
+// The braces in comments, literals and annotation arguments don't end the header.
+func header(content string) string {
+	depth := 0
+	for i := 0; i < len(content); i++ {
+		switch {
+		case strings.HasPrefix(content[i:], "//"):
+			end := strings.IndexByte(content[i:], '\n')
+			if end < 0 {
+				return content
+			}
+			i += end
+		case strings.HasPrefix(content[i:], "/*"):
+			end := strings.Index(content[i+2:], "*/")
+			if end < 0 {
+				return content
+			}
+			i += end + 3
+		case content[i] == '"' || content[i] == '\'':
+			quote := content[i]
+			for i++; i < len(content) && content[i] != quote; i++ {
+				if content[i] == '\\' {
+					i++
+				}
+			}
+		case content[i] == '(':
+			depth++
+		case content[i] == ')':
+			depth--
+		case content[i] == '{' && depth <= 0:
+			return content[:i]
+		}
+	}
+	return content
+}
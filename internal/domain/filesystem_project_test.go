@@ -119,3 +119,89 @@ func SkipOnWindows(t *testing.T) {
 		t.Skip("Skipping test on Windows")
 	}
 }
+
+func TestFilesystemProject_Classes_SkipsBuildOutput(t *testing.T) {
+	tmp := t.TempDir()
+	write(t, tmp, "src/main/java/Foo.java", "class Foo {}")
+	write(t, tmp, "target/generated-sources/Bar.java", "class Bar {}")
+	write(t, tmp, "src/main/java/org/build/Baz.java", "class Baz {}")
+
+	classes, err := NewFilesystem(tmp).Classes()
+
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Foo", "Baz"}, names(classes))
+}
+
+func TestFilesystemProject_Classes_SkipsGeneratedCode(t *testing.T) {
+	tmp := t.TempDir()
+	write(t, tmp, "Foo.java", "class Foo {}")
+	write(t, tmp, "Bar.java", "@javax.annotation.processing.Generated(\"protoc\")\nclass Bar {}")
+	write(t, tmp, "Baz.java", "// Code generated by a tool. DO NOT EDIT.\nclass Baz {}")
+	write(t, tmp, "Entity.java", "class Entity { @GeneratedValue Long id; }")
+
+	classes, err := NewFilesystem(tmp).Classes()
+
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Foo", "Entity"}, names(classes))
+}
+
+func TestFilesystemProject_Classes_KeepsClassesWithMarksInsideOfTypes(t *testing.T) {
+	tmp := t.TempDir()
+	write(t, tmp, "Foo.java", "/* The service. */\n@SuppressWarnings({\"unchecked\"})\nclass Foo {\n  @Generated\n  void run() {}\n}")
+	write(t, tmp, "Bar.java", "class Bar {\n  /** DO NOT EDIT this constant. */\n  static final int X = 1;\n}")
+	write(t, tmp, "Baz.java", "class Baz {\n  String s = \"@Generated, DO NOT EDIT\";\n}")
+	write(t, tmp, "Qux.java", "@SuppressWarnings({\"all\"})\n@Generated(\"tool\")\nclass Qux {}")
+
+	classes, err := NewFilesystem(tmp).Classes()
+
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Foo", "Bar", "Baz"}, names(classes))
+}
+
+func TestFilesystemProject_Classes_HonorsGitignore(t *testing.T) {
+	tmp := t.TempDir()
+	write(t, tmp, ".gitignore", "scratch/\n")
+	write(t, tmp, "src/Foo.java", "class Foo {}")
+	write(t, tmp, "scratch/Bar.java", "class Bar {}")
+
+	classes, err := NewFilesystem(tmp).Classes()
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Foo"}, names(classes))
+}
+
+func TestFilesystemProject_Classes_AppliesIncludeAndExclude(t *testing.T) {
+	tmp := t.TempDir()
+	write(t, tmp, "src/main/java/Foo.java", "class Foo {}")
+	write(t, tmp, "src/main/java/FooHelper.java", "class FooHelper {}")
+	write(t, tmp, "src/test/java/FooTest.java", "class FooTest {}")
+
+	classes, err := NewFilesystem(tmp).WithInclude("src/main/**").WithExclude("*Helper.java").Classes()
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Foo"}, names(classes))
+}
+
+func TestFilesystemProject_Classes_FailsOnBadPattern(t *testing.T) {
+	tmp := t.TempDir()
+	write(t, tmp, "Foo.java", "class Foo {}")
+
+	_, err := NewFilesystem(tmp).WithExclude("[").Classes()
+
+	require.Error(t, err)
+}
+
+func write(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func names(classes []Class) []string {
+	res := make([]string, 0, len(classes))
+	for _, c := range classes {
+		res = append(res, c.Name())
+	}
+	return res
+}
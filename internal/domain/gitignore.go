@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// gitignore holds the rules of all .gitignore files found in a project so far.
+type gitignore struct {
+	rules []rule
+}
+
+// rule is a single line of a .gitignore file.
+type rule struct {
+	base     string
+	pattern  string
+	negated  bool
+	dir      bool
+	anchored bool
+}
+
+// load reads the .gitignore file of the directory, if there is one.
+// The directory is relative to the project root, "." stands for the root itself.
+func (g *gitignore) load(root, dir string) error {
+	file := filepath.Join(root, filepath.FromSlash(dir), ".gitignore")
+	content, err := os.ReadFile(filepath.Clean(file))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	scanner := bufio.NewScanner(strings.NewReader(string(content)))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		r := rule{base: dir}
+		if strings.HasPrefix(line, "!") {
+			r.negated = true
+			line = line[1:]
+		}
+		line = strings.TrimPrefix(line, "\\")
+		if strings.HasSuffix(line, "/") {
+			r.dir = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		r.anchored = strings.Contains(line, "/")
+		r.pattern = strings.TrimPrefix(line, "/")
+		if _, err = path.Match(r.pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q in %s: %w", line, file, err)
+		}
+		g.rules = append(g.rules, r)
+	}
+	return nil
+}
+
+// ignored reports whether the path relative to the project root is ignored.
+// As in git, the last matching rule wins, and a negated rule brings the path back.
+func (g *gitignore) ignored(name string, dir bool) bool {
+	res := false
+	for _, r := range g.rules {
+		if r.dir && !dir {
+			continue
+		}
+		sub := name
+		if r.base != "." {
+			if !strings.HasPrefix(name, r.base+"/") {
+				continue
+			}
+			sub = strings.TrimPrefix(name, r.base+"/")
+		}
+		var ok bool
+		if r.anchored {
+			ok, _ = segments(strings.Split(r.pattern, "/"), strings.Split(sub, "/"))
+		} else {
+			ok, _ = path.Match(r.pattern, path.Base(sub))
+		}
+		if ok {
+			res = !r.negated
+		}
+	}
+	return res
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitignore_IgnoresMatchingPaths(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".gitignore"), []byte("# comment\n*.log\ntmp/\n/Local.java\nsrc/**/Draft*.java\n"), 0o600))
+	ignore := &gitignore{}
+
+	require.NoError(t, ignore.load(root, "."))
+
+	assert.True(t, ignore.ignored("app.log", false))
+	assert.True(t, ignore.ignored("src/tmp", true))
+	assert.False(t, ignore.ignored("src/tmp", false))
+	assert.True(t, ignore.ignored("Local.java", false))
+	assert.False(t, ignore.ignored("src/Local.java", false))
+	assert.True(t, ignore.ignored("src/main/DraftFoo.java", false))
+	assert.False(t, ignore.ignored("src/main/Foo.java", false))
+}
+
+func TestGitignore_NegatedRuleBringsPathBack(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".gitignore"), []byte("*.java\n!Keep.java\n"), 0o600))
+	ignore := &gitignore{}
+
+	require.NoError(t, ignore.load(root, "."))
+
+	assert.True(t, ignore.ignored("Foo.java", false))
+	assert.False(t, ignore.ignored("Keep.java", false))
+}
+
+func TestGitignore_AppliesNestedRulesToTheirDirectoryOnly(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "module"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "module", ".gitignore"), []byte("Foo.java\n"), 0o600))
+	ignore := &gitignore{}
+
+	require.NoError(t, ignore.load(root, "module"))
+
+	assert.True(t, ignore.ignored("module/src/Foo.java", false))
+	assert.False(t, ignore.ignored("other/Foo.java", false))
+}
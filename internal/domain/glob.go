@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"path"
+	"strings"
+)
+
+// matches reports whether the slash-separated relative path matches the glob pattern.
+// Besides the syntax of path.Match, the pattern supports "**" that matches any number of directories.
+// A pattern without a slash matches the base name of the path, e.g. "*Test.java".
+func matches(pattern, name string) (bool, error) {
+	pattern = strings.TrimPrefix(pattern, "/")
+	if !strings.Contains(pattern, "/") {
+		return path.Match(pattern, path.Base(name))
+	}
+	return segments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+// segments matches the path segments against the pattern segments one by one.
+func segments(pattern, name []string) (bool, error) {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				ok, err := segments(pattern[1:], name[i:])
+				if err != nil || ok {
+					return ok, err
+				}
+			}
+			return false, nil
+		}
+		if len(name) == 0 {
+			return false, nil
+		}
+		ok, err := path.Match(pattern[0], name[0])
+		if err != nil || !ok {
+			return false, err
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0, nil
+}
+
+// matchesAny reports whether the path matches at least one of the patterns.
+func matchesAny(patterns []string, name string) (bool, error) {
+	for _, p := range patterns {
+		ok, err := matches(p, name)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
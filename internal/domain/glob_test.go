@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatches_Globs(t *testing.T) {
+	cases := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"src/main/**", "src/main/java/Foo.java", true},
+		{"src/main/**", "src/test/java/FooTest.java", false},
+		{"**/generated/**", "src/main/generated/Foo.java", true},
+		{"**/*.java", "Foo.java", true},
+		{"src/*/Foo.java", "src/main/Foo.java", true},
+		{"src/*/Foo.java", "src/main/java/Foo.java", false},
+		{"*Test.java", "src/test/java/FooTest.java", true},
+		{"/Foo.java", "Foo.java", true},
+	}
+	for _, c := range cases {
+		ok, err := matches(c.pattern, c.name)
+		require.NoError(t, err)
+		assert.Equal(t, c.want, ok, "pattern %q against %q", c.pattern, c.name)
+	}
+}
+
+func TestMatches_FailsOnBadPattern(t *testing.T) {
+	_, err := matches("src/[main/**", "src/main/Foo.java")
+
+	require.Error(t, err)
+}
@@ -20,7 +20,7 @@ func NewMirrorProject(original *FSProj, mirrorPath string) (*MirrorProject, erro
 	if err != nil {
 		return nil, fmt.Errorf("failed to copy project: %w", err)
 	}
-	return &MirrorProject{mirror: NewFilesystem(mirrorPath)}, nil
+	return &MirrorProject{mirror: original.at(mirrorPath)}, nil
 }
 
 // Classes retrieves all Java classes from the mirrored project.
@@ -0,0 +1,200 @@
+package domain
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cqfn/refrax/internal/diff"
+)
+
+// OverlayProject keeps the changes of the classes in memory, the files of the origin project stay intact.
+type OverlayProject struct {
+	root    string
+	origin  Project
+	mu      sync.Mutex
+	changes map[string]string
+}
+
+// overlayClass is a class of the origin project whose new content is kept in the overlay.
+type overlayClass struct {
+	origin  Class
+	overlay *OverlayProject
+}
+
+// NewOverlayProject creates an overlay over the origin project located in the root directory.
+func NewOverlayProject(root string, origin Project) *OverlayProject {
+	return &OverlayProject{root: root, origin: origin, changes: make(map[string]string)}
+}
+
+// Classes retrieves the classes of the origin project, their content includes the changes of the overlay.
+func (p *OverlayProject) Classes() ([]Class, error) {
+	all, err := p.origin.Classes()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]Class, 0, len(all))
+	for _, c := range all {
+		res = append(res, &overlayClass{origin: c, overlay: p})
+	}
+	return res, nil
+}
+
+// Changed retrieves the classes whose content differs from the origin, sorted by path.
+func (p *OverlayProject) Changed() ([]Class, error) {
+	all, err := p.Classes()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]Class, 0)
+	for _, c := range all {
+		if c.Content() != c.(*overlayClass).origin.Content() {
+			res = append(res, c)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Path() < res[j].Path() })
+	return res, nil
+}
+
+// Materialize writes a copy of the project with all the changes into the directory,
+// e.g. to run checks against it. The .git directory, the build output and the files ignored by .gitignore
+// are left out, symbolic links are copied as links.
+func (p *OverlayProject) Materialize(dir string) error {
+	if err := replicate(p.root, dir); err != nil {
+		return fmt.Errorf("failed to copy project %s to %s: %w", p.root, dir, err)
+	}
+	changed, err := p.Changed()
+	if err != nil {
+		return err
+	}
+	for _, c := range changed {
+		rel, rerr := p.relative(c.Path())
+		if rerr != nil {
+			return rerr
+		}
+		if err = os.WriteFile(filepath.Join(dir, rel), []byte(c.Content()), 0o600); err != nil {
+			return fmt.Errorf("failed to write class %s to %s: %w", c.Name(), dir, err)
+		}
+	}
+	return nil
+}
+
+// Patch returns a unified diff of all the changes, with paths relative to the project root.
+func (p *OverlayProject) Patch() (string, error) {
+	changed, err := p.Changed()
+	if err != nil {
+		return "", err
+	}
+	var res strings.Builder
+	for _, c := range changed {
+		rel, rerr := p.relative(c.Path())
+		if rerr != nil {
+			return "", rerr
+		}
+		res.WriteString(diff.Unified(filepath.ToSlash(rel), c.(*overlayClass).origin.Content(), c.Content()))
+	}
+	return res.String(), nil
+}
+
+// String returns the string representation of the OverlayProject.
+func (p *OverlayProject) String() string {
+	return fmt.Sprintf("overlay of %v", p.origin)
+}
+
+// replicate copies the files of the project in the root directory that git would track into the directory.
+// The .mvn directory is copied despite being a build one, since the Maven wrapper keeps its settings there.
+func replicate(root, dir string) error {
+	ignore := &gitignore{}
+	return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		slashed := filepath.ToSlash(rel)
+		target := filepath.Join(dir, rel)
+		if entry.IsDir() {
+			if rel != "." && ((isBuild(slashed) && entry.Name() != ".mvn") || ignore.ignored(slashed, true)) {
+				return filepath.SkipDir
+			}
+			if err = ignore.load(root, slashed); err != nil {
+				return err
+			}
+			return os.MkdirAll(target, 0o750)
+		}
+		if ignore.ignored(slashed, false) {
+			return nil
+		}
+		if entry.Type()&fs.ModeSymlink != 0 {
+			link, lerr := os.Readlink(path)
+			if lerr != nil {
+				return fmt.Errorf("failed to read link %s: %w", path, lerr)
+			}
+			return os.Symlink(link, target)
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		return os.WriteFile(target, content, info.Mode().Perm())
+	})
+}
+
+// relative returns the path of the class relative to the project root.
+func (p *OverlayProject) relative(path string) (string, error) {
+	root, err := filepath.Abs(p.root)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve project root %s: %w", p.root, err)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path %s: %w", path, err)
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("class %s is outside of the project %s", path, p.root)
+	}
+	return rel, nil
+}
+
+// Name implements Class.
+func (c *overlayClass) Name() string {
+	return c.origin.Name()
+}
+
+// Path implements Class.
+func (c *overlayClass) Path() string {
+	return c.origin.Path()
+}
+
+// Content returns the content of the class with the changes of the overlay.
+func (c *overlayClass) Content() string {
+	c.overlay.mu.Lock()
+	content, ok := c.overlay.changes[c.origin.Path()]
+	c.overlay.mu.Unlock()
+	if ok {
+		return content
+	}
+	return c.origin.Content()
+}
+
+// SetContent keeps the new content of the class in the overlay.
+func (c *overlayClass) SetContent(content string) error {
+	c.overlay.mu.Lock()
+	defer c.overlay.mu.Unlock()
+	c.overlay.changes[c.origin.Path()] = content
+	return nil
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverlayProject_SetContent_LeavesFilesIntact(t *testing.T) {
+	tmp := t.TempDir()
+	write(t, tmp, "src/Foo.java", "class Foo {}\n")
+	overlay := NewOverlayProject(tmp, NewFilesystem(tmp))
+	classes, err := overlay.Classes()
+	require.NoError(t, err)
+
+	require.NoError(t, classes[0].SetContent("final class Foo {}\n"))
+
+	assert.Equal(t, "final class Foo {}\n", classes[0].Content())
+	content, err := os.ReadFile(filepath.Join(tmp, "src", "Foo.java"))
+	require.NoError(t, err)
+	assert.Equal(t, "class Foo {}\n", string(content))
+}
+
+func TestOverlayProject_Changed_ReturnsChangedClassesOnly(t *testing.T) {
+	tmp := t.TempDir()
+	write(t, tmp, "Foo.java", "class Foo {}\n")
+	write(t, tmp, "Bar.java", "class Bar {}\n")
+	overlay := NewOverlayProject(tmp, NewFilesystem(tmp))
+	classes, err := overlay.Classes()
+	require.NoError(t, err)
+	for _, c := range classes {
+		require.NoError(t, c.SetContent(c.Content()))
+		if c.Name() == "Bar" {
+			require.NoError(t, c.SetContent("final class Bar {}\n"))
+		}
+	}
+
+	changed, err := overlay.Changed()
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Bar"}, names(changed))
+}
+
+func TestOverlayProject_Patch_RendersUnifiedDiff(t *testing.T) {
+	tmp := t.TempDir()
+	write(t, tmp, "src/Foo.java", "class Foo {}\n")
+	overlay := NewOverlayProject(tmp, NewFilesystem(tmp))
+	classes, err := overlay.Classes()
+	require.NoError(t, err)
+	require.NoError(t, classes[0].SetContent("final class Foo {}\n"))
+
+	patch, err := overlay.Patch()
+
+	require.NoError(t, err)
+	assert.Equal(t, "--- a/src/Foo.java\n+++ b/src/Foo.java\n@@ -1,1 +1,1 @@\n-class Foo {}\n+final class Foo {}\n", patch)
+}
+
+func TestOverlayProject_Materialize_WritesProjectWithChanges(t *testing.T) {
+	tmp := t.TempDir()
+	write(t, tmp, "pom.xml", "<project/>")
+	write(t, tmp, "src/Foo.java", "class Foo {}\n")
+	overlay := NewOverlayProject(tmp, NewFilesystem(tmp))
+	classes, err := overlay.Classes()
+	require.NoError(t, err)
+	require.NoError(t, classes[0].SetContent("final class Foo {}\n"))
+	dir := t.TempDir()
+
+	err = overlay.Materialize(dir)
+
+	require.NoError(t, err)
+	pom, err := os.ReadFile(filepath.Join(dir, "pom.xml"))
+	require.NoError(t, err)
+	assert.Equal(t, "<project/>", string(pom))
+	foo, err := os.ReadFile(filepath.Join(dir, "src", "Foo.java"))
+	require.NoError(t, err)
+	assert.Equal(t, "final class Foo {}\n", string(foo))
+}
+
+func TestOverlayProject_Materialize_SkipsIgnoredFilesAndKeepsLinks(t *testing.T) {
+	tmp := t.TempDir()
+	write(t, tmp, ".gitignore", "*.log\n")
+	write(t, tmp, ".git/HEAD", "ref: refs/heads/master")
+	write(t, tmp, "target/classes/Foo.class", "binary")
+	write(t, tmp, "node_modules/lib/index.js", "module")
+	write(t, tmp, "build.log", "log")
+	write(t, tmp, ".mvn/wrapper/maven-wrapper.properties", "distributionUrl=maven")
+	write(t, tmp, "src/Foo.java", "class Foo {}\n")
+	require.NoError(t, os.Symlink(filepath.Join("src", "Foo.java"), filepath.Join(tmp, "Foo.java")))
+	overlay := NewOverlayProject(tmp, NewFilesystem(tmp))
+	dir := t.TempDir()
+
+	err := overlay.Materialize(dir)
+
+	require.NoError(t, err)
+	for _, skipped := range []string{".git", "target", "node_modules", "build.log"} {
+		assert.NoFileExists(t, filepath.Join(dir, skipped), "%s should not be copied", skipped)
+		assert.NoDirExists(t, filepath.Join(dir, skipped), "%s should not be copied", skipped)
+	}
+	assert.FileExists(t, filepath.Join(dir, ".mvn", "wrapper", "maven-wrapper.properties"))
+	link, err := os.Readlink(filepath.Join(dir, "Foo.java"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("src", "Foo.java"), link)
+}
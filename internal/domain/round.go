@@ -0,0 +1,23 @@
+package domain
+
+// Round is a successful refactoring round: the classes the fixer changed and the reviewer accepted.
+type Round struct {
+	Number  int
+	Changes []Change
+}
+
+// Change is a class changed in a round along with the suggestions applied to it.
+type Change struct {
+	Class       Class
+	Suggestions []Suggestion
+	Diff        int
+}
+
+// Diff returns the total diff size of the round.
+func (r *Round) Diff() int {
+	total := 0
+	for _, c := range r.Changes {
+		total += c.Diff
+	}
+	return total
+}
@@ -15,6 +15,10 @@ func Token(path, provider string) string {
 		return find(path, "DEEPSEEK_API_KEY")
 	case "openai":
 		return find(path, "OPENAI_API_KEY")
+	case "openai-compatible":
+		return find(path, "OPENAI_COMPATIBLE_API_KEY")
+	case "anthropic":
+		return find(path, "ANTHROPIC_API_KEY")
 	case "mock":
 		return find(path, "MOCK_TOKEN")
 	case "ollama":
@@ -183,3 +183,31 @@ func TestProviderToken_ReadsMockTokenFromEnvironmentVariable(t *testing.T) {
 	assert.NotEmpty(t, token, "expected mock token to be set")
 	assert.Equal(t, "mock-token-value", token, "expected mock token to match environment variable")
 }
+
+func TestProviderToken_AnthropicTokenPresent(t *testing.T) {
+	tmp := t.TempDir()
+	anthropic := "anthropic-token-value"
+	env := filepath.Join(tmp, ".env")
+	err := os.WriteFile(env, fmt.Appendf(nil, "ANTHROPIC_API_KEY=%s", anthropic), 0o600)
+	require.NoError(t, err)
+
+	result := Token(env, "anthropic")
+
+	assert.Equal(t, anthropic, result)
+}
+
+func TestProviderToken_AnthropicTokenFromEnvironment(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "anthropic-env-token")
+
+	result := Token(t.TempDir(), "anthropic")
+
+	assert.Equal(t, "anthropic-env-token", result)
+}
+
+func TestProviderToken_OpenAICompatibleTokenFromEnvironment(t *testing.T) {
+	t.Setenv("OPENAI_COMPATIBLE_API_KEY", "compatible-env-token")
+
+	result := Token(t.TempDir(), "openai-compatible")
+
+	assert.Equal(t, "compatible-env-token", result)
+}
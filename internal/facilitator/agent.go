@@ -1,18 +1,23 @@
 package facilitator
 
 import (
+	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/cqfn/refrax/internal/brain"
+	"github.com/cqfn/refrax/internal/diff"
 	"github.com/cqfn/refrax/internal/domain"
+	"github.com/cqfn/refrax/internal/java"
 	"github.com/cqfn/refrax/internal/log"
-	"github.com/cqfn/refrax/internal/prompts"
+	"github.com/cqfn/refrax/internal/protocol"
 	"github.com/cqfn/refrax/internal/stats"
-	"github.com/cqfn/refrax/internal/util"
 )
 
-const MAX_TOKENS = 6_000
+// fanout is the number of requests the facilitator sends to the critic or the fixer at once,
+// unless the concurrency of the brains is limited.
+const fanout = 8
 
 type agent struct {
 	brain    brain.Brain
@@ -22,11 +27,34 @@ type agent struct {
 	reviewer domain.Reviewer
 	frounds  int
 	attempts int
+	rounds   func(domain.Round) error
+	// remote tells that the facilitator serves clients whose files it can't reach,
+	// so it keeps the classes in memory instead of writing them to their files.
+	remote bool
+	// slots bound the number of requests sent to the critic or the fixer at once, since the requests
+	// that wait for the brains would run into the timeout of the client.
+	slots chan struct{}
+}
+
+// settings are the parameters of a refactoring job the facilitator follows in every round.
+type settings struct {
+	size  int
+	mode  string
+	limit int
+	// index is the symbol index of the project, rebuilt in every round.
+	index *index
+	// examples are the classes whose style the fixer follows.
+	examples []domain.Class
+	// baseline holds the problems the checks found before the refactoring by the paths of their classes,
+	// these problems are not blamed on it.
+	baseline map[string][]string
 }
 
 type fix struct {
 	err   error
 	class domain.Class
+	// covered are the suggestions the fixer reported as applied, empty if it doesn't report them.
+	covered []domain.Suggestion
 }
 
 type critique struct {
@@ -36,6 +64,11 @@ type critique struct {
 }
 
 func (a *agent) Refactor(job *domain.Job) (*domain.Artifacts, error) {
+	return a.facilitate(context.Background(), job)
+}
+
+// facilitate runs the refactoring rounds and reports the progress to the client that streams the request.
+func (a *agent) facilitate(ctx context.Context, job *domain.Job) (*domain.Artifacts, error) {
 	size, err := job.MaxSize()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get max size limit: %w", err)
@@ -44,17 +77,56 @@ func (a *agent) Refactor(job *domain.Job) (*domain.Artifacts, error) {
 		a.log.Warn("Received a message that is not related to refactoring, ignoring")
 		return nil, fmt.Errorf("received a message that is not related to refactoring")
 	}
-	diff := 0
-	result := make([]domain.Class, 0)
+	prio, err := newPrioritizer(job, a.brain, a.log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to choose prioritizer: %w", err)
+	}
+	limit, err := job.TokenLimit()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token limit: %w", err)
+	}
+	set := settings{size: size, mode: job.FixMode(), limit: limit}
+	if set.mode != "full" && set.mode != "edits" {
+		return nil, fmt.Errorf("unknown fix mode %q, expected one of: full, edits", set.mode)
+	}
+	ws := newWorkspace(job, a.remote)
 	attempts := a.attempts
+	before := &domain.Artifacts{}
+	if attempts > 0 || automatic(job) {
+		a.log.Info("Running the checks before any change...")
+		before, err = a.review(ctx, ws)
+		if err != nil {
+			return nil, fmt.Errorf("failed to run the checks before refactoring: %w", err)
+		}
+	}
+	set.examples, err = a.exemplars(ws, job, before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to choose example classes: %w", err)
+	}
+	total := 0
+	result := make([]domain.Class, 0)
+	reverted := make([]string, 0)
 	if attempts <= 0 {
 		a.log.Info("Number of attempts less or equal zero (%d), skipping refactoring", attempts)
 	} else {
 		a.log.Info("Starting refactoring with max-size=%d and attempts=%d", size, attempts)
+		set.baseline, err = a.baseline(ws, before)
+		if err != nil {
+			return nil, err
+		}
 	}
-	for diff < size && attempts > 0 {
-		a.log.Info("Refactoring attempt %d/%d, current diff %d/%d", a.attempts-attempts+1, a.attempts, diff, size)
-		c, err := a.criticizeAll(job.Classes, size)
+	for total < size && attempts > 0 {
+		if err = ctx.Err(); err != nil {
+			return nil, fmt.Errorf("refactoring was stopped: %w", err)
+		}
+		a.log.Info("Refactoring attempt %d/%d, current diff %d/%d", a.attempts-attempts+1, a.attempts, total, size)
+		protocol.Progress(ctx, fmt.Sprintf("refactoring attempt %d/%d, current diff %d/%d", a.attempts-attempts+1, a.attempts, total, size))
+		classes, err := ws.classes()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get classes to refactor: %w", err)
+		}
+		set.index = newIndex(classes, a.log)
+		c, err := a.criticizeAll(ctx, classes, set)
 		if err != nil {
 			return nil, fmt.Errorf("failed to criticize classes: %w", err)
 		}
@@ -66,7 +138,7 @@ func (a *agent) Refactor(job *domain.Job) (*domain.Artifacts, error) {
 			}
 			return res, nil
 		}
-		important, err := a.mostImportant(c)
+		important, err := prio.prioritize(ctx, c)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get most frequent suggestions: %w", err)
 		}
@@ -79,40 +151,83 @@ func (a *agent) Refactor(job *domain.Job) (*domain.Artifacts, error) {
 			return res, nil
 		}
 		a.log.Info("Received %d most important suggestions", len(important))
-		refactored, changed, err := a.refactorAll(important, size)
+		protocol.Progress(ctx, fmt.Sprintf("chose suggestions for %d classes to fix", len(important)))
+		snap := newSnapshot(ws)
+		changes, changed, err := a.refactorAll(ctx, snap, important, set)
 		if err != nil {
 			return nil, fmt.Errorf("failed to fix all suggestions: %w", err)
 		}
-		diff += changed
-		err = a.repair(refactored)
-		result = append(result, refactored...)
+		refactored := make([]domain.Class, 0, len(changes))
+		for _, c := range changes {
+			refactored = append(refactored, c.Class)
+		}
+		total += changed
+		broken, err := a.repair(ctx, snap, refactored, set)
 		if err != nil {
 			return nil, fmt.Errorf("failed to stabilize refactored classes: %w", err)
 		}
+		kept := make([]domain.Change, 0, len(changes))
+		for _, c := range changes {
+			if slices.Contains(broken, c.Class.Path()) {
+				total -= c.Diff
+				continue
+			}
+			c.Class = snap.class(c.Class.Path())
+			kept = append(kept, c)
+			result = append(result, c.Class)
+		}
+		changes = kept
+		reverted = append(reverted, broken...)
+		if a.rounds != nil && len(changes) > 0 {
+			round := domain.Round{Number: a.attempts - attempts + 1, Changes: changes}
+			if err = a.rounds(round); err != nil {
+				return nil, fmt.Errorf("failed to record refactoring round %d: %w", round.Number, err)
+			}
+		}
 		attempts--
 	}
+	final, err := ws.changed(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect refactored classes: %w", err)
+	}
+	descr := &domain.Description{Text: "refactored classes"}
+	if len(reverted) > 0 {
+		descr.Text = fmt.Sprintf("refactored classes, reverted classes that failed the checks: %s", strings.Join(reverted, ", "))
+		descr.Meta = map[string]any{"reverted": strings.Join(reverted, ",")}
+	}
 	res := &domain.Artifacts{
-		Descr:   &domain.Description{Text: "refactored classes"},
-		Classes: result,
+		Descr:   descr,
+		Classes: final,
 	}
 	return res, nil
 }
 
-func (a *agent) criticizeAll(classes []domain.Class, size int) ([]critique, error) {
+// criticizeAll asks the critic to review the classes concurrently.
+// The classes larger than the token limit are reviewed in chunks of members.
+func (a *agent) criticizeAll(ctx context.Context, classes []domain.Class, set settings) ([]critique, error) {
 	nclasses := len(classes)
-	a.log.Info("Received request for refactoring, number of attached files: %d, max-size: %d", nclasses, size)
+	a.log.Info("Received request for refactoring, number of attached files: %d, max-size: %d", nclasses, set.size)
 	improvements := make([]critique, 0, nclasses)
 	ch := make(chan critique, nclasses)
 	reviewed := 0
 	for _, class := range classes {
 		tokens, _ := stats.Tokens(class.Content())
 		a.log.Debug("Class %s has %d tokens", class.Path(), tokens)
-		if tokens < MAX_TOKENS {
-			reviewed++
-			go a.criticize(class, ch)
-		} else {
-			a.log.Warn("Class %s (%s) has too many tokens (%d), skipping review", class.Name(), class.Path(), tokens)
-		}
+		reviewed++
+		go func() {
+			release, err := a.occupy(ctx)
+			if err != nil {
+				ch <- critique{err: fmt.Errorf("review of class %s was stopped: %w", class.Path(), err), class: class}
+				return
+			}
+			defer release()
+			if tokens < set.limit {
+				a.criticize(ctx, class, set, ch)
+			} else {
+				a.log.Info("Class %s (%s) has %d tokens, more than the limit of %d, reviewing it in chunks", class.Name(), class.Path(), tokens, set.limit)
+				a.criticizeChunks(ctx, class, set, ch)
+			}
+		}()
 	}
 	a.log.Info("Number of classes to review: %d", reviewed)
 	for range reviewed {
@@ -126,19 +241,23 @@ func (a *agent) criticizeAll(classes []domain.Class, size int) ([]critique, erro
 }
 
 // criticize sends a review request to the critic and returns the suggestions or an error.
-func (a *agent) criticize(class domain.Class, ch chan<- critique) {
+func (a *agent) criticize(ctx context.Context, class domain.Class, set settings, ch chan<- critique) {
 	a.log.Info("Received class for refactoring: %q", class.Path())
+	tokens, _ := stats.Tokens(class.Content())
+	related, _ := set.index.context(class, min(set.limit/4, set.limit-tokens))
 	job := domain.Job{
 		Descr: &domain.Description{
 			Text: "refactor the class",
 		},
 		Classes: []domain.Class{class},
+		Context: related,
 	}
-	artifacts, err := a.critic.Review(&job)
+	artifacts, err := a.critic.Review(ctx, &job)
 	if err != nil {
 		ch <- critique{err: fmt.Errorf("failed to ask critic: %w", err), class: class}
 		return
 	}
+	protocol.Progress(ctx, fmt.Sprintf("critic found %d suggestions for class %s", len(artifacts.Suggestions), class.Path()))
 	if len(artifacts.Suggestions) == 0 {
 		a.log.Info("No suggestions found for class %s", class.Path())
 		ch <- critique{err: nil, class: class}
@@ -153,195 +272,466 @@ func (a *agent) criticize(class domain.Class, ch chan<- critique) {
 	}
 }
 
+// criticizeChunks asks the critic to review a large class chunk by chunk.
+// The lines of the suggestions refer to the class rather than to the chunks.
+func (a *agent) criticizeChunks(ctx context.Context, class domain.Class, set settings, ch chan<- critique) {
+	related, used := set.index.context(class, set.limit/4)
+	parts, err := chunks(class, set.limit-used)
+	if err != nil {
+		a.log.Warn("Can't review class %s in chunks, skipping review: %v", class.Path(), err)
+		ch <- critique{class: class}
+		return
+	}
+	suggestions := make([]domain.Suggestion, 0)
+	for i, part := range parts {
+		if !part.fits {
+			a.log.Warn("Chunk %d/%d of class %s is larger than the limit of %d tokens, skipping review", i+1, len(parts), class.Path(), set.limit)
+			continue
+		}
+		view, lines := part.numbered()
+		job := domain.Job{
+			Descr: &domain.Description{
+				Text: "refactor the class",
+				Meta: map[string]any{"skeleton": part.skeleton},
+			},
+			Classes: []domain.Class{domain.NewInMemoryClass(class.Name(), class.Path(), view)},
+			Context: related,
+		}
+		artifacts, rerr := a.critic.Review(ctx, &job)
+		if rerr != nil {
+			ch <- critique{err: fmt.Errorf("failed to ask critic: %w", rerr), class: class}
+			return
+		}
+		for _, s := range artifacts.Suggestions {
+			suggestions = append(suggestions, part.located(s, lines))
+		}
+		a.log.Info("Received %d suggestions for chunk %d/%d of class %s", len(artifacts.Suggestions), i+1, len(parts), class.Path())
+	}
+	protocol.Progress(ctx, fmt.Sprintf("critic found %d suggestions for class %s in %d chunks", len(suggestions), class.Path(), len(parts)))
+	ch <- critique{class: class, suggestions: suggestions}
+}
+
 // refactorAll processes all improvements concurrently, ensuring that the total changes do not exceed the specified size limit.
-func (a *agent) refactorAll(improvements []critique, size int) ([]domain.Class, int, error) {
-	refactored := make([]domain.Class, 0)
+// It returns the changed classes along with the suggestions applied to them and the total diff.
+func (a *agent) refactorAll(ctx context.Context, snap *snapshot, improvements []critique, set settings) ([]domain.Change, int, error) {
+	refactored := make([]domain.Change, 0)
 	fixChannel := make(chan fix, len(improvements))
 	send := make(map[string]critique, 0)
 	for _, imp := range improvements {
 		send[imp.class.Path()] = imp
-		go a.refactor(imp, fixChannel)
+		go a.refactor(ctx, imp, set, fixChannel)
 	}
 	changed := 0
 	for range len(send) {
 		fixRes := <-fixChannel
 		if fixRes.err != nil {
-			panic(fmt.Sprintf("Failed to fix class: %v", fixRes.err))
+			return nil, 0, fmt.Errorf("failed to fix class: %w", fixRes.err)
 		}
 		path := fixRes.class.Path()
 		class := send[path].class
-		if changed >= size {
-			a.log.Warn("Refactoring class %s would exceed max-size of %d (current %d), skipping refactoring", class.Name(), size, changed)
+		if changed >= set.size {
+			a.log.Warn("Refactoring class %s would exceed max-size of %d (current %d), skipping refactoring", class.Name(), set.size, changed)
 			continue
 		}
 		modified := fixRes.class
-		refactored = append(refactored, modified)
-		diff := util.Diff(class.Content(), modified.Content())
-		a.log.Info("Fixed class %s (%s), changed content (diff %d)", modified.Name(), modified.Path(), diff)
-		changed += diff
+		delta := diff.Measure(class.Content(), modified.Content())
+		applied := send[path].suggestions
+		if len(fixRes.covered) > 0 {
+			applied = fixRes.covered
+		}
+		refactored = append(refactored, domain.Change{Class: modified, Suggestions: applied, Diff: delta.Size()})
+		a.log.Info("Fixed class %s (%s), changed content (diff %d, lines %s)", modified.Name(), modified.Path(), delta.Size(), delta)
+		protocol.Progress(ctx, fmt.Sprintf("fixer changed class %s (diff %d, lines %s)", modified.Path(), delta.Size(), delta))
+		changed += delta.Size()
 	}
 	for _, c := range refactored {
-		class := domain.NewFSClass(c.Name(), c.Path())
-		err := class.SetContent(c.Content())
+		class := send[c.Class.Path()].class
 		a.log.Info("Setting content for class %s (%s)", class.Name(), class.Path())
-		if err != nil {
+		if err := snap.save(class, c.Class.Content()); err != nil {
 			return nil, 0, fmt.Errorf("failed to set content for class %s: %w", class.Name(), err)
 		}
 	}
 	return refactored, changed, nil
 }
 
-// doFixSuggestions sends a refactor request to the fixer and returns the modified class or an error.
-func (a *agent) refactor(c critique, ch chan<- fix) {
-	class := c.class
-	suggestions := c.suggestions
+// refactor sends a refactor request to the fixer and returns the modified class or an error.
+func (a *agent) refactor(ctx context.Context, c critique, set settings, ch chan<- fix) {
+	release, err := a.occupy(ctx)
+	if err != nil {
+		ch <- fix{fmt.Errorf("fix of class %s was stopped: %w", c.class.Path(), err), nil, nil}
+		return
+	}
+	defer release()
+	modified, err := a.fixClass(ctx, c.class, c.suggestions, set)
+	if err != nil {
+		ch <- fix{fmt.Errorf("failed to ask fixer: %w", err), nil, nil}
+		return
+	}
+	ch <- fix{nil, modified.Classes[0], modified.Suggestions}
+}
+
+// fixClass asks the fixer to apply the suggestions to the class.
+// The classes larger than the token limit are fixed in chunks of members.
+// The related classes and the examples take up to a quarter of the limit each, as long as the class leaves room for them.
+func (a *agent) fixClass(ctx context.Context, class domain.Class, suggestions []domain.Suggestion, set settings) (*domain.Artifacts, error) {
+	tokens, _ := stats.Tokens(class.Content())
+	if tokens >= set.limit {
+		related, used := set.index.context(class, set.limit/4)
+		parts, err := chunks(class, set.limit-used)
+		if err == nil {
+			return a.fixChunks(ctx, class, parts, suggestions, related, used, set)
+		}
+		a.log.Warn("Can't fix class %s in chunks, sending it whole: %v", class.Path(), err)
+	}
+	related, used := set.index.context(class, min(set.limit/4, set.limit-tokens))
 	job := domain.Job{
 		Descr: &domain.Description{
 			Text: "fix the class",
+			Meta: map[string]any{"fix-mode": set.mode},
 		},
 		Classes:     []domain.Class{class},
 		Suggestions: suggestions,
-		Examples:    []domain.Class{nil},
+		Examples:    exemplify(class, set.examples, min(set.limit/4, set.limit-tokens-used)),
+		Context:     related,
 	}
-	modified, err := a.fixer.Fix(&job)
-	if err != nil {
-		ch <- fix{fmt.Errorf("failed to ask fixer: %w", err), nil}
-		return
+	return a.fixer.Fix(ctx, &job)
+}
+
+// fixChunks asks the fixer to apply the suggestions to each chunk they are about and stitches the fixed chunks
+// back into the class. If the stitched class is not valid Java, the class is left as is.
+// The outlines of the related classes take the reserved tokens of the limit.
+func (a *agent) fixChunks(
+	ctx context.Context, class domain.Class, parts []chunk, suggestions []domain.Suggestion, outlines []domain.Class, reserved int, set settings,
+) (*domain.Artifacts, error) {
+	lines := strings.Split(class.Content(), "\n")
+	views := make([]string, 0)
+	covered := make([]domain.Suggestion, 0)
+	for i := len(parts) - 1; i >= 0; i-- {
+		part := parts[i]
+		view, numbers := part.numbered()
+		related := make([]domain.Suggestion, 0)
+		for _, s := range suggestions {
+			if part.covers(s) {
+				related = append(related, part.relocated(s, numbers))
+			}
+		}
+		if len(related) == 0 {
+			continue
+		}
+		if !part.fits {
+			a.log.Warn("Chunk %d/%d of class %s is larger than the limit of %d tokens, skipping fix", i+1, len(parts), class.Path(), set.limit)
+			continue
+		}
+		taken, _ := stats.Tokens(view + part.skeleton)
+		job := domain.Job{
+			Descr: &domain.Description{
+				Text: "fix the class",
+				Meta: map[string]any{"fix-mode": set.mode, "skeleton": part.skeleton},
+			},
+			Classes:     []domain.Class{domain.NewInMemoryClass(class.Name(), class.Path(), view)},
+			Suggestions: related,
+			Examples:    exemplify(class, set.examples, min(set.limit/4, set.limit-taken-reserved)),
+			Context:     outlines,
+		}
+		fixed, err := a.fixer.Fix(ctx, &job)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fix chunk %d/%d of class %s: %w", i+1, len(parts), class.Path(), err)
+		}
+		content := fixed.Classes[0].Content()
+		members, err := part.fixed(content)
+		if err != nil {
+			a.log.Warn("Fix of chunk %d/%d of class %s is rejected: %v", i+1, len(parts), class.Path(), err)
+			continue
+		}
+		lines = slices.Replace(lines, part.start()-1, part.end(), members...)
+		views = append(views, content)
+		for _, s := range fixed.Suggestions {
+			same := func(o domain.Suggestion) bool { return o.Text == s.Text }
+			if k := slices.IndexFunc(suggestions, same); k >= 0 && !slices.ContainsFunc(covered, same) {
+				covered = append(covered, suggestions[k])
+			}
+		}
+		a.log.Info("Fixed chunk %d/%d of class %s", i+1, len(parts), class.Path())
+	}
+	stitched := strings.Join(imported(lines, views), "\n")
+	descr := fmt.Sprintf("Fix for class %s in %d chunks", class.Name(), len(parts))
+	if _, err := java.Parse(stitched); err != nil {
+		a.log.Warn("Stitched chunks of class %s are not valid Java, leaving the class as is: %v", class.Path(), err)
+		stitched = class.Content()
+		covered = nil
+		descr = fmt.Sprintf("Fix for class %s in chunks is rejected: %v", class.Name(), err)
 	}
-	ch <- fix{nil, modified.Classes[0]}
+	res := &domain.Artifacts{
+		Descr:       &domain.Description{Text: descr},
+		Classes:     []domain.Class{domain.NewInMemoryClass(class.Name(), class.Path(), stitched)},
+		Suggestions: covered,
+	}
+	return res, nil
 }
 
 // repair chcks whether the refactored classes have any errors and tries to fix them if any.
-func (a *agent) repair(refactored []domain.Class) error {
+// When the rounds of fixing run out, it reverts the classes that still fail the checks and returns their paths.
+func (a *agent) repair(ctx context.Context, snap *snapshot, refactored []domain.Class, set settings) ([]string, error) {
 	a.log.Info("Fixing refactored classes, number of classes: %d", len(refactored))
-	artifacts, err := a.reviewer.Review()
+	artifacts, err := a.review(ctx, snap.ws)
+	if err != nil {
+		return nil, fmt.Errorf("failed to review project: %w", err)
+	}
+	suggestions, err := a.fresh(snap.ws, artifacts.Suggestions, set.baseline)
 	if err != nil {
-		return fmt.Errorf("failed to review project: %w", err)
+		return nil, err
 	}
-	suggestions := artifacts.Suggestions
 	a.log.Info("Received %d suggestions from reviewer", len(suggestions))
+	protocol.Progress(ctx, fmt.Sprintf("reviewer found %d problems", len(suggestions)))
 	for _, s := range suggestions {
 		a.log.Info("Received suggestion: %s: %s", s.ClassPath, s.Text)
 	}
 	counter := a.frounds
 	for len(suggestions) > 0 {
-		if err != nil {
-			return fmt.Errorf("failed to review project: %w", err)
+		if counter <= 0 {
+			a.log.Warn("Refactored classes still have %d problems after %d rounds of fixing", len(suggestions), a.frounds)
+			return a.rollback(ctx, snap, refactored, suggestions, set)
 		}
 		perclass := a.understandClasses(refactored, suggestions)
 		for k, v := range perclass {
-			job := domain.Job{
-				Descr: &domain.Description{
-					Text: "fix the class",
-				},
-				Classes:     []domain.Class{k},
-				Suggestions: v,
-			}
-			fixed, uerr := a.fixer.Fix(&job)
+			fixed, uerr := a.fixClass(ctx, snap.class(k.Path()), v, set)
 			if uerr != nil {
-				return fmt.Errorf("failed to fix project: %w", uerr)
+				return nil, fmt.Errorf("failed to fix project: %w", uerr)
 			}
 			updated := fixed.Classes[0]
-			class := domain.NewFSClass(k.Name(), k.Path())
-			a.log.Info("Updating class %s (%s) with new content", class.Name(), class.Path())
-			uerr = class.SetContent(updated.Content())
-			if uerr != nil {
-				return fmt.Errorf("failed to set content for class %s: %w", class.Name(), uerr)
+			a.log.Info("Updating class %s (%s) with new content", k.Name(), k.Path())
+			if uerr = snap.save(k, updated.Content()); uerr != nil {
+				return nil, fmt.Errorf("failed to set content for class %s: %w", k.Name(), uerr)
 			}
 		}
 		counter--
-		if counter < 0 {
-			return fmt.Errorf("too many rounds of fixing errors, stopping")
+		artifacts, err = a.review(ctx, snap.ws)
+		if err != nil {
+			return nil, fmt.Errorf("failed to review project: %w", err)
+		}
+		suggestions, err = a.fresh(snap.ws, artifacts.Suggestions, set.baseline)
+		if err != nil {
+			return nil, err
 		}
-		artifacts, err = a.reviewer.Review()
-		suggestions = artifacts.Suggestions
+		protocol.Progress(ctx, fmt.Sprintf("reviewer round %d/%d found %d problems", a.frounds-counter, a.frounds, len(suggestions)))
 	}
-	return nil
+	return []string{}, nil
 }
 
-func (a *agent) understandClasses(clases []domain.Class, suggestions []domain.Suggestion) map[domain.Class][]domain.Suggestion {
-	res := make(map[domain.Class][]domain.Suggestion, len(clases))
-	for _, s := range suggestions {
-		for _, c := range clases {
-			actual := s.ClassPath
-			expected := c.Path()
-			if actual == expected || strings.Contains(actual, expected) || strings.Contains(expected, actual) {
-				a.log.Info("Associating suggestion %q with class %s", s.Text, c.Path())
-				res[c] = append(res[c], s)
-				break
-			}
+// rollback reverts the classes the reviewer still complains about.
+// If the checks keep failing, e.g. because a class broke its callers, it bisects over the rest of the changed classes
+// to find the ones to revert. It fails if the checks find new problems even with all the changes reverted.
+func (a *agent) rollback(
+	ctx context.Context, snap *snapshot, refactored []domain.Class, suggestions []domain.Suggestion, set settings,
+) ([]string, error) {
+	blamed := make([]string, 0)
+	for c := range a.understandClasses(refactored, suggestions) {
+		blamed = append(blamed, c.Path())
+	}
+	slices.Sort(blamed)
+	if len(blamed) > 0 {
+		a.log.Warn("Reverting classes that still fail the checks: %s", strings.Join(blamed, ", "))
+		protocol.Progress(ctx, fmt.Sprintf("reverting %d classes that still fail the checks", len(blamed)))
+		if err := snap.revert(blamed...); err != nil {
+			return nil, err
+		}
+		passed, err := a.passes(ctx, snap.ws, set.baseline)
+		if err != nil {
+			return nil, err
+		}
+		if passed {
+			return blamed, nil
 		}
 	}
-	return res
+	rest := make([]string, 0)
+	for _, path := range snap.paths() {
+		if !slices.Contains(blamed, path) {
+			rest = append(rest, path)
+		}
+	}
+	if err := snap.revert(rest...); err != nil {
+		return nil, err
+	}
+	passed, err := a.passes(ctx, snap.ws, set.baseline)
+	if err != nil {
+		return nil, err
+	}
+	if !passed {
+		return nil, fmt.Errorf("checks fail even with all the changes of the round reverted")
+	}
+	broken, err := a.bisect(ctx, snap, rest, set)
+	if err != nil {
+		return nil, err
+	}
+	return append(blamed, broken...), nil
 }
 
-func (a *agent) mostImportant(improvements []critique) ([]critique, error) {
-	a.log.Info("Grouping all suggestions...")
-	all := make([]domain.Suggestion, 0, len(improvements))
-	for _, imp := range improvements {
-		all = append(all, imp.suggestions...)
+// bisect finds the reverted classes whose changes break the checks and brings back the changes of the others.
+// It tries the changes in groups and splits a group in halves when it fails, so that a few broken classes
+// among many need only a few runs of the checks.
+func (a *agent) bisect(ctx context.Context, snap *snapshot, paths []string, set settings) ([]string, error) {
+	broken := make([]string, 0)
+	queue := [][]string{paths}
+	for len(queue) > 0 {
+		group := queue[0]
+		queue = queue[1:]
+		if len(group) == 0 {
+			continue
+		}
+		if err := snap.restore(group...); err != nil {
+			return nil, err
+		}
+		passed, err := a.passes(ctx, snap.ws, set.baseline)
+		if err != nil {
+			return nil, err
+		}
+		if passed {
+			a.log.Info("Kept changes of classes: %s", strings.Join(group, ", "))
+			continue
+		}
+		if err = snap.revert(group...); err != nil {
+			return nil, err
+		}
+		if len(group) == 1 {
+			a.log.Warn("Reverting class %s, its changes fail the checks", group[0])
+			protocol.Progress(ctx, fmt.Sprintf("reverting class %s that fails the checks", group[0]))
+			broken = append(broken, group[0])
+			continue
+		}
+		half := len(group) / 2
+		queue = append(queue, group[:half], group[half:])
 	}
-	type groupPromptData struct {
-		Suggestions []domain.Suggestion
+	return broken, nil
+}
+
+// occupy waits for a free slot to send a request to the critic or the fixer and returns the function
+// that frees the slot. It fails if the context is canceled while waiting.
+func (a *agent) occupy(ctx context.Context) (func(), error) {
+	if a.slots == nil {
+		return func() {}, nil
 	}
-	prompt := prompts.User{
-		Data: groupPromptData{
-			Suggestions: all,
-		},
-		Name: "facilitator/group.md.tmpl",
+	select {
+	case a.slots <- struct{}{}:
+		return func() { <-a.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
 	}
-	important, err := a.brain.Ask(prompt.String())
+}
+
+// passes checks whether the reviewer finds no problems in the workspace besides the ones of the baseline.
+func (a *agent) passes(ctx context.Context, ws workspace, baseline map[string][]string) (bool, error) {
+	artifacts, err := a.review(ctx, ws)
 	if err != nil {
-		return nil, fmt.Errorf("failed to ask the brain to group suggestions: %w", err)
-	}
-	type choosePromoptData struct {
-		Groupped string
+		return false, fmt.Errorf("failed to review project: %w", err)
 	}
-	prompt = prompts.User{
-		Data: choosePromoptData{
-			Groupped: important,
-		},
-		Name: "facilitator/choose.md.tmpl",
+	suggestions, err := a.fresh(ws, artifacts.Suggestions, baseline)
+	if err != nil {
+		return false, err
 	}
-	a.log.Info("Choosing the most important suggestions...")
-	important, err = a.brain.Ask(prompt.String())
+	return len(suggestions) == 0, nil
+}
+
+// baseline returns the problems the checks found before any change by the paths of their classes.
+// The problems that are not about a class, e.g. a failed build, are never part of the baseline.
+func (a *agent) baseline(ws workspace, before *domain.Artifacts) (map[string][]string, error) {
+	classes, err := ws.classes()
 	if err != nil {
-		return nil, fmt.Errorf("failed to ask brain for most frequent suggestion: %w", err)
+		return nil, fmt.Errorf("failed to get classes to compare with the baseline: %w", err)
 	}
-	classSuggestions := make(map[string][]string, 0)
-	for s := range strings.SplitSeq(strings.ReplaceAll(important, "\r\n", "\n"), "\n") {
-		a.log.Info("Suggestion to consider: %s", s)
-		if !strings.Contains(s, ":") {
-			a.log.Warn("Can't find a delimiter ':'")
-			continue
+	res := make(map[string][]string, len(before.Suggestions))
+	for _, s := range before.Suggestions {
+		if path := about(classes, s); path != "" {
+			res[path] = append(res[path], normalized(s.Text))
 		}
-		split := strings.Split(s, ":")
-		if len(split) < 2 {
-			a.log.Warn("Skipping suggestion without class name: %s", s)
-			continue
+	}
+	if len(res) > 0 {
+		a.log.Warn("The checks fail before any change, their problems with %d classes are not blamed on the refactoring", len(res))
+	}
+	return res, nil
+}
+
+// fresh returns the suggestions of the reviewer that are not in the baseline. Since the reviewer words
+// the same problem differently every time, a class is compared by the number of its problems: if it has no more
+// problems than before, they are all taken as the old ones, otherwise the ones worded as before are dropped,
+// unless all of them are worded as before.
+// The suggestions that are not about a class are always fresh.
+func (a *agent) fresh(ws workspace, suggestions []domain.Suggestion, baseline map[string][]string) ([]domain.Suggestion, error) {
+	if len(baseline) == 0 {
+		return suggestions, nil
+	}
+	classes, err := ws.classes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get classes to compare with the baseline: %w", err)
+	}
+	perclass := make(map[string][]domain.Suggestion)
+	for _, s := range suggestions {
+		path := about(classes, s)
+		perclass[path] = append(perclass[path], s)
+	}
+	res := make([]domain.Suggestion, 0, len(suggestions))
+	for _, s := range suggestions {
+		path := about(classes, s)
+		known := baseline[path]
+		unknown := func(o domain.Suggestion) bool { return !slices.Contains(known, normalized(o.Text)) }
+		switch {
+		case path == "":
+			res = append(res, s)
+		case len(perclass[path]) <= len(known):
+		case unknown(s) || !slices.ContainsFunc(perclass[path], unknown):
+			res = append(res, s)
 		}
-		className := strings.TrimSpace(split[0])
-		classSuggestion := strings.TrimSpace(split[1])
-		classSuggestions[className] = append(classSuggestions[className], classSuggestion)
 	}
-	a.log.Info("Received %d suggestions from brain", len(classSuggestions))
-	ires := make([]critique, 0)
-	for k, v := range classSuggestions {
-		class, err := find(improvements, k)
-		if err != nil {
-			a.log.Warn("Class %s not found in improvements, skipping: %v", k, err)
-			continue
+	return res, nil
+}
+
+// normalized returns the text of a problem in lower case with the whitespace collapsed.
+func normalized(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
+
+// about returns the path of the class the suggestion is about, or the path of the suggestion itself
+// if it is about a file that is not a class of the project, matching the paths as understandClasses does.
+// It returns "" for a suggestion that is not about any file.
+func about(classes []domain.Class, s domain.Suggestion) string {
+	if s.ClassPath == "" {
+		return ""
+	}
+	for _, c := range classes {
+		if s.ClassPath == c.Path() || strings.Contains(s.ClassPath, c.Path()) || strings.Contains(c.Path(), s.ClassPath) {
+			return c.Path()
 		}
-		var suggetions []domain.Suggestion
-		for _, s := range v {
-			suggetions = append(suggetions, *domain.NewSuggestion(s, class.Path()))
+	}
+	return s.ClassPath
+}
+
+// review asks the reviewer to check the project in the directory the workspace prepares.
+func (a *agent) review(ctx context.Context, ws workspace) (*domain.Artifacts, error) {
+	dir, cleanup, err := ws.checkout()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare project for review: %w", err)
+	}
+	defer cleanup()
+	job := domain.Job{Descr: &domain.Description{Text: "review"}}
+	if dir != "" {
+		job.Descr.Meta = map[string]any{"dir": dir}
+	}
+	return a.reviewer.Review(ctx, &job)
+}
+
+func (a *agent) understandClasses(clases []domain.Class, suggestions []domain.Suggestion) map[domain.Class][]domain.Suggestion {
+	res := make(map[domain.Class][]domain.Suggestion, len(clases))
+	for _, s := range suggestions {
+		for _, c := range clases {
+			actual := s.ClassPath
+			expected := c.Path()
+			if actual == expected || strings.Contains(actual, expected) || strings.Contains(expected, actual) {
+				a.log.Info("Associating suggestion %q with class %s", s.Text, c.Path())
+				res[c] = append(res[c], s)
+				break
+			}
 		}
-		ires = append(ires, critique{
-			class:       class,
-			suggestions: suggetions,
-		})
 	}
-	return ires, nil
+	return res
 }
 
 func find(c []critique, path string) (domain.Class, error) {
@@ -357,6 +747,22 @@ func find(c []critique, path string) (domain.Class, error) {
 	return nil, fmt.Errorf("class %s not found in improvements %d", path, len(all))
 }
 
+// original returns the suggestion of the critic with the same text, so that its category, severity and lines are kept.
+// The brain may rephrase a suggestion, then a new one with the text alone is created.
+func original(c []critique, path, text string) domain.Suggestion {
+	for _, imp := range c {
+		if imp.class.Path() != path {
+			continue
+		}
+		for _, s := range imp.suggestions {
+			if strings.EqualFold(strings.TrimSpace(s.Text), text) {
+				return s
+			}
+		}
+	}
+	return *domain.NewSuggestion(text, path)
+}
+
 func (c *critique) String() string {
 	return fmt.Sprintf("class=%s (suggestions=%v)", c.class.Path(), len(c.suggestions))
 }
@@ -0,0 +1,224 @@
+package facilitator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cqfn/refrax/internal/domain"
+	"github.com/cqfn/refrax/internal/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// checks is a reviewer that complains about every class whose file contains the word "broken",
+// and once more if it contains the word "mistyped".
+// The complaint is about the caller, if one is set, as a compiler would report a broken call.
+type checks struct {
+	paths  []string
+	caller string
+}
+
+// stubborn is a fixer that never manages to fix a class.
+type stubborn struct{}
+
+func (c *checks) Review(_ context.Context, _ *domain.Job) (*domain.Artifacts, error) {
+	res := &domain.Artifacts{Descr: &domain.Description{Text: "review"}}
+	for _, p := range c.paths {
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		blamed := p
+		if c.caller != "" {
+			blamed = c.caller
+		}
+		if strings.Contains(string(content), "broken") {
+			res.Suggestions = append(res.Suggestions, *domain.NewSuggestion("cannot find symbol", blamed))
+		}
+		if strings.Contains(string(content), "mistyped") {
+			res.Suggestions = append(res.Suggestions, *domain.NewSuggestion("incompatible types", blamed))
+		}
+	}
+	return res, nil
+}
+
+func (s *stubborn) Fix(_ context.Context, job *domain.Job) (*domain.Artifacts, error) {
+	return &domain.Artifacts{Classes: job.Classes}, nil
+}
+
+func TestRepair_RevertsClassesThatStillFailChecks(t *testing.T) {
+	paths := classes(t, "Foo.java", "Bar.java", "Baz.java")
+	a := &agent{log: log.NewMock(), fixer: &stubborn{}, reviewer: &checks{paths: paths}, frounds: 2}
+	snap := newSnapshot(&disk{})
+	refactored := change(t, snap, map[string]string{paths[0]: "fixed", paths[1]: "broken", paths[2]: "fixed"})
+
+	reverted, err := a.repair(context.Background(), snap, refactored, settings{mode: "full", limit: 6_000})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{paths[1]}, reverted)
+	assert.Equal(t, []string{"fixed", "original", "fixed"}, contents(t, paths))
+}
+
+func TestRepair_BisectsClassesThatBreakOthers(t *testing.T) {
+	paths := classes(t, "A.java", "B.java", "C.java", "D.java", "E.java")
+	a := &agent{log: log.NewMock(), fixer: &stubborn{}, reviewer: &checks{paths: paths, caller: "Main.java"}, frounds: 1}
+	snap := newSnapshot(&disk{})
+	refactored := change(t, snap, map[string]string{
+		paths[0]: "fixed", paths[1]: "fixed", paths[2]: "fixed", paths[3]: "broken", paths[4]: "fixed",
+	})
+
+	reverted, err := a.repair(context.Background(), snap, refactored, settings{mode: "full", limit: 6_000})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{paths[3]}, reverted)
+	assert.Equal(t, []string{"fixed", "fixed", "fixed", "original", "fixed"}, contents(t, paths))
+}
+
+func TestRepair_KeepsClassesThatPassChecks(t *testing.T) {
+	paths := classes(t, "Foo.java")
+	a := &agent{log: log.NewMock(), fixer: &stubborn{}, reviewer: &checks{paths: paths}, frounds: 1}
+	snap := newSnapshot(&disk{})
+	refactored := change(t, snap, map[string]string{paths[0]: "fixed"})
+
+	reverted, err := a.repair(context.Background(), snap, refactored, settings{mode: "full", limit: 6_000})
+
+	require.NoError(t, err)
+	assert.Empty(t, reverted)
+	assert.Equal(t, []string{"fixed"}, contents(t, paths))
+}
+
+func TestRepair_FailsWhenChecksFailWithoutChanges(t *testing.T) {
+	paths := classes(t, "Foo.java", "Broken.java")
+	require.NoError(t, os.WriteFile(paths[1], []byte("broken"), 0o600))
+	a := &agent{log: log.NewMock(), fixer: &stubborn{}, reviewer: &checks{paths: paths, caller: "Main.java"}, frounds: 1}
+	snap := newSnapshot(&disk{})
+	refactored := change(t, snap, map[string]string{paths[0]: "fixed"})
+
+	_, err := a.repair(context.Background(), snap, refactored, settings{mode: "full", limit: 6_000})
+
+	assert.Error(t, err)
+}
+
+func TestRepair_IgnoresProblemsThatWereThereBefore(t *testing.T) {
+	paths := classes(t, "Foo.java", "Bar.java", "Broken.java")
+	require.NoError(t, os.WriteFile(paths[2], []byte("broken"), 0o600))
+	a := &agent{log: log.NewMock(), fixer: &stubborn{}, reviewer: &checks{paths: paths}, frounds: 1}
+	snap := newSnapshot(&disk{})
+	before, err := a.review(context.Background(), snap.ws)
+	require.NoError(t, err)
+	baseline, err := a.baseline(snap.ws, before)
+	require.NoError(t, err)
+	refactored := change(t, snap, map[string]string{paths[0]: "broken", paths[1]: "fixed"})
+
+	reverted, err := a.repair(context.Background(), snap, refactored, settings{mode: "full", limit: 6_000, baseline: baseline})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{paths[0]}, reverted)
+	assert.Equal(t, []string{"original", "fixed", "broken"}, contents(t, paths))
+}
+
+func TestRepair_RevertsNewProblemsOfClassThatFailedBefore(t *testing.T) {
+	paths := classes(t, "Foo.java", "Bar.java")
+	require.NoError(t, os.WriteFile(paths[1], []byte("broken"), 0o600))
+	a := &agent{log: log.NewMock(), fixer: &stubborn{}, reviewer: &checks{paths: paths}, frounds: 1}
+	snap := newSnapshot(&disk{})
+	before, err := a.review(context.Background(), snap.ws)
+	require.NoError(t, err)
+	baseline, err := a.baseline(snap.ws, before)
+	require.NoError(t, err)
+	refactored := change(t, snap, map[string]string{paths[0]: "fixed", paths[1]: "broken and mistyped"})
+
+	reverted, err := a.repair(context.Background(), snap, refactored, settings{mode: "full", limit: 6_000, baseline: baseline})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{paths[1]}, reverted)
+	assert.Equal(t, []string{"fixed", "broken"}, contents(t, paths))
+}
+
+func TestFresh_NeverHidesProblemsThatAreNotAboutClass(t *testing.T) {
+	a := &agent{log: log.NewMock()}
+	baseline := map[string][]string{"Foo.java": {"cannot find symbol"}}
+	suggestions := []domain.Suggestion{
+		*domain.NewSuggestion("Cannot  find symbol", "Foo.java"),
+		*domain.NewSuggestion("build failed", ""),
+	}
+
+	res, err := a.fresh(newWorkspace(&domain.Job{}, false), suggestions, baseline)
+
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.Equal(t, "build failed", res[0].Text)
+}
+
+func TestCriticizeAll_BoundsRequestsInFlight(t *testing.T) {
+	ctc := &busy{}
+	a := &agent{log: log.NewMock(), critic: ctc, slots: make(chan struct{}, 2)}
+	all := make([]domain.Class, 0)
+	for _, name := range []string{"A", "B", "C", "D", "E", "F"} {
+		all = append(all, domain.NewInMemoryClass(name, name+".java", "class "+name+" {}"))
+	}
+
+	res, err := a.criticizeAll(context.Background(), all, settings{limit: 6_000})
+
+	require.NoError(t, err)
+	assert.Len(t, res, len(all))
+	assert.LessOrEqual(t, ctc.peak.Load(), int32(2), "No more requests than slots should be in flight")
+}
+
+// busy is a critic that takes a while to review a class and remembers how many reviews ran at once.
+type busy struct {
+	running atomic.Int32
+	peak    atomic.Int32
+}
+
+func (b *busy) Review(_ context.Context, _ *domain.Job) (*domain.Artifacts, error) {
+	now := b.running.Add(1)
+	defer b.running.Add(-1)
+	for {
+		peak := b.peak.Load()
+		if now <= peak || b.peak.CompareAndSwap(peak, now) {
+			break
+		}
+	}
+	time.Sleep(200 * time.Millisecond)
+	return &domain.Artifacts{}, nil
+}
+
+func classes(t *testing.T, names ...string) []string {
+	t.Helper()
+	dir := t.TempDir()
+	res := make([]string, 0, len(names))
+	for _, n := range names {
+		p := filepath.Join(dir, n)
+		require.NoError(t, os.WriteFile(p, []byte("original"), 0o600))
+		res = append(res, p)
+	}
+	return res
+}
+
+func change(t *testing.T, snap *snapshot, changes map[string]string) []domain.Class {
+	t.Helper()
+	res := make([]domain.Class, 0, len(changes))
+	for p, content := range changes {
+		c := domain.NewInMemoryClass(filepath.Base(p), p, content)
+		require.NoError(t, snap.save(c, content))
+		res = append(res, c)
+	}
+	return res
+}
+
+func contents(t *testing.T, paths []string) []string {
+	t.Helper()
+	res := make([]string, 0, len(paths))
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		require.NoError(t, err)
+		res = append(res, string(data))
+	}
+	return res
+}
@@ -0,0 +1,197 @@
+package facilitator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cqfn/refrax/internal/domain"
+	"github.com/cqfn/refrax/internal/java"
+	"github.com/cqfn/refrax/internal/stats"
+)
+
+// chunk is a run of adjacent members of a large class. The agents see a chunk along with the lines around
+// the members, e.g. the package, the imports and the type declaration, and the skeleton of the class.
+type chunk struct {
+	layout   *java.Layout
+	members  []java.Member
+	skeleton string
+	// fits tells whether the chunk fits into the token limit, a single member may be larger than the limit.
+	fits bool
+}
+
+// chunks splits the class into chunks of members that fit into the token limit together with the lines
+// around the members and the skeleton of the class.
+func chunks(class domain.Class, limit int) ([]chunk, error) {
+	layout, err := java.Split(class.Content())
+	if err != nil {
+		return nil, fmt.Errorf("failed to split class %s into members: %w", class.Path(), err)
+	}
+	if len(layout.Members) == 0 {
+		return nil, fmt.Errorf("class %s has no members to split it by", class.Path())
+	}
+	sk := skeleton(layout)
+	frame, err := stats.Tokens(chunk{layout: layout}.view())
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tokens of class %s: %w", class.Path(), err)
+	}
+	described, err := stats.Tokens(sk)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tokens of class %s: %w", class.Path(), err)
+	}
+	budget := limit - frame - described
+	if budget <= 0 {
+		return nil, fmt.Errorf("class %s doesn't fit into %d tokens even without its members", class.Path(), limit)
+	}
+	res := make([]chunk, 0)
+	current := chunk{layout: layout, skeleton: sk, fits: true}
+	used := 0
+	for _, m := range layout.Members {
+		tokens, terr := stats.Tokens(layout.Text(m))
+		if terr != nil {
+			return nil, fmt.Errorf("failed to count tokens of class %s: %w", class.Path(), terr)
+		}
+		if len(current.members) > 0 && (current.owner() != m.Owner || used+tokens > budget) {
+			res = append(res, current)
+			current = chunk{layout: layout, skeleton: sk, fits: true}
+			used = 0
+		}
+		current.members = append(current.members, m)
+		used += tokens
+		current.fits = used <= budget
+	}
+	return append(res, current), nil
+}
+
+// skeleton lists the members of the class by their signatures, so that the agents know what the omitted members are.
+func skeleton(layout *java.Layout) string {
+	var res strings.Builder
+	owner := ""
+	for _, m := range layout.Members {
+		if m.Owner != owner {
+			owner = m.Owner
+			fmt.Fprintf(&res, "%s:\n", owner)
+		}
+		fmt.Fprintf(&res, "- %s\n", m.Signature)
+	}
+	return strings.TrimSuffix(res.String(), "\n")
+}
+
+func (c chunk) owner() string {
+	return c.members[0].Owner
+}
+
+func (c chunk) start() int {
+	return c.members[0].Start
+}
+
+func (c chunk) end() int {
+	return c.members[len(c.members)-1].End
+}
+
+// view returns the code of the class without the members of the other chunks.
+func (c chunk) view() string {
+	res, _ := c.numbered()
+	return res
+}
+
+// numbered returns the view of the chunk along with the line of the class for each line of the view.
+func (c chunk) numbered() (string, []int) {
+	omitted := make([]bool, len(c.layout.Lines)+1)
+	for _, m := range c.layout.Members {
+		if slices.Contains(c.members, m) {
+			continue
+		}
+		for i := m.Start; i <= m.End; i++ {
+			omitted[i] = true
+		}
+	}
+	kept := make([]string, 0, len(c.layout.Lines))
+	lines := make([]int, 0, len(c.layout.Lines))
+	for i, line := range c.layout.Lines {
+		if !omitted[i+1] {
+			kept = append(kept, line)
+			lines = append(lines, i+1)
+		}
+	}
+	return strings.Join(kept, "\n"), lines
+}
+
+// covers tells whether the suggestion is about the members of the chunk.
+// A suggestion without lines may be about any member.
+func (c chunk) covers(s domain.Suggestion) bool {
+	return s.Start == 0 || (s.Start <= c.end() && max(s.End, s.Start) >= c.start())
+}
+
+// located moves the lines of a suggestion from the view of the chunk to the class.
+// A suggestion without lines refers to the whole chunk.
+func (c chunk) located(s domain.Suggestion, lines []int) domain.Suggestion {
+	if s.Start < 1 || s.Start > len(lines) {
+		s.Start, s.End = c.start(), c.end()
+		return s
+	}
+	s.Start = lines[s.Start-1]
+	s.End = lines[min(max(s.End, 1), len(lines))-1]
+	s.End = max(s.End, s.Start)
+	return s
+}
+
+// relocated moves the lines of a suggestion from the class to the view of the chunk.
+func (c chunk) relocated(s domain.Suggestion, lines []int) domain.Suggestion {
+	if s.Start == 0 {
+		return s
+	}
+	start, end := c.start(), c.end()
+	from := slices.Index(lines, min(max(s.Start, start), end))
+	to := slices.Index(lines, min(max(s.End, start), end))
+	s.Start, s.End = from+1, max(to, from)+1
+	return s
+}
+
+// fixed finds the members of the chunk in the fixed view, i.e. the lines of the body of its type.
+func (c chunk) fixed(view string) ([]string, error) {
+	bodies, err := java.Bodies(view)
+	if err != nil {
+		return nil, fmt.Errorf("fixed chunk is not valid Java: %w", err)
+	}
+	lines := strings.Split(view, "\n")
+	for _, b := range bodies {
+		if b.Owner == c.owner() {
+			if b.Close <= b.Open {
+				return nil, fmt.Errorf("body of %s in the fixed chunk is on one line", b.Owner)
+			}
+			return lines[b.Open : b.Close-1], nil
+		}
+	}
+	return nil, fmt.Errorf("fixed chunk has no type %s", c.owner())
+}
+
+// imported adds the imports of the fixed views that the class doesn't have yet,
+// since fixed members may use new types.
+func imported(lines []string, views []string) []string {
+	known := make(map[string]bool)
+	last := -1
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "import ") {
+			known[trimmed] = true
+			last = i
+		} else if strings.HasPrefix(trimmed, "package ") && last < 0 {
+			last = i
+		}
+	}
+	added := make([]string, 0)
+	for _, view := range views {
+		for _, line := range strings.Split(view, "\n") {
+			trimmed := strings.TrimSpace(line)
+			if strings.HasPrefix(trimmed, "import ") && !known[trimmed] {
+				known[trimmed] = true
+				added = append(added, trimmed)
+			}
+		}
+	}
+	if len(added) == 0 {
+		return lines
+	}
+	return slices.Insert(lines, last+1, added...)
+}
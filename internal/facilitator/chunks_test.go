@@ -0,0 +1,165 @@
+package facilitator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cqfn/refrax/internal/domain"
+	"github.com/cqfn/refrax/internal/log"
+	"github.com/cqfn/refrax/internal/stats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reviewing is a critic that suggests to simplify the line with the given text, if the code has it.
+type reviewing struct {
+	text  string
+	views []string
+}
+
+// rewriting is a fixer that applies the suggestions by replacing one text with another and adding an import.
+type rewriting struct {
+	from, to string
+	jobs     []*domain.Job
+}
+
+func (r *reviewing) Review(_ context.Context, job *domain.Job) (*domain.Artifacts, error) {
+	code := job.Classes[0].Content()
+	r.views = append(r.views, code)
+	res := &domain.Artifacts{Descr: &domain.Description{Text: "review"}}
+	for i, line := range strings.Split(code, "\n") {
+		if strings.Contains(line, r.text) {
+			s := domain.NewSuggestion("Simplify the statement", job.Classes[0].Path())
+			s.Start, s.End = i+1, i+1
+			res.Suggestions = append(res.Suggestions, *s)
+		}
+	}
+	return res, nil
+}
+
+func (r *rewriting) Fix(_ context.Context, job *domain.Job) (*domain.Artifacts, error) {
+	r.jobs = append(r.jobs, job)
+	code := strings.Replace(job.Classes[0].Content(), "package a;\n", "package a;\n\nimport java.util.List;\n", 1)
+	code = strings.ReplaceAll(code, r.from, r.to)
+	class := domain.NewInMemoryClass(job.Classes[0].Name(), job.Classes[0].Path(), code)
+	return &domain.Artifacts{Classes: []domain.Class{class}}, nil
+}
+
+func TestChunks_SplitsLargeClassByMembers(t *testing.T) {
+	class, limit := large(t)
+
+	parts, err := chunks(class, limit)
+
+	require.NoError(t, err)
+	require.Len(t, parts, 3)
+	for i, part := range parts {
+		assert.True(t, part.fits)
+		view := part.view()
+		assert.Contains(t, view, fmt.Sprintf("public int second%d()", i))
+		assert.NotContains(t, view, fmt.Sprintf("public int second%d()", (i+1)%3))
+		assert.True(t, strings.HasPrefix(view, "package a;\n\npublic class Big {\n"))
+		assert.True(t, strings.HasSuffix(view, "}\n"))
+		assert.Contains(t, part.skeleton, "- public int second2()")
+	}
+}
+
+func TestCriticizeChunks_RefersSuggestionsToLinesOfClass(t *testing.T) {
+	class, limit := large(t)
+	critic := &reviewing{text: "return 2"}
+	a := &agent{log: log.NewMock(), critic: critic}
+	ch := make(chan critique, 1)
+
+	a.criticizeChunks(t.Context(), class, settings{limit: limit}, ch)
+
+	res := <-ch
+	require.NoError(t, res.err)
+	require.Len(t, res.suggestions, 1)
+	line := strings.Split(class.Content(), "\n")[res.suggestions[0].Start-1]
+	assert.Contains(t, line, "return 2")
+	assert.Len(t, critic.views, 3)
+}
+
+func TestFixChunks_StitchesFixedChunksIntoClass(t *testing.T) {
+	class, limit := large(t)
+	fixer := &rewriting{from: "return 2;", to: "return List.of(2).get(0);"}
+	a := &agent{log: log.NewMock(), fixer: fixer}
+	parts, err := chunks(class, limit)
+	require.NoError(t, err)
+	s := domain.NewSuggestion("Simplify the statement", class.Path())
+	s.Start = strings.Count(class.Content()[:strings.Index(class.Content(), "return 2")], "\n") + 1
+	s.End = s.Start
+
+	res, err := a.fixChunks(context.Background(), class, parts, []domain.Suggestion{*s}, nil, 0, settings{mode: "full", limit: limit})
+
+	require.NoError(t, err)
+	require.Len(t, fixer.jobs, 1)
+	sk, ok := fixer.jobs[0].Param("skeleton")
+	require.True(t, ok)
+	assert.Contains(t, sk, "- public int second0()")
+	assert.NotContains(t, fixer.jobs[0].Classes[0].Content(), "second0")
+	assert.Equal(t, "    return 2;", strings.Split(fixer.jobs[0].Classes[0].Content(), "\n")[fixer.jobs[0].Suggestions[0].Start-1])
+	expected := strings.Replace(class.Content(), "return 2;", "return List.of(2).get(0);", 1)
+	expected = strings.Replace(expected, "package a;\n", "package a;\nimport java.util.List;\n", 1)
+	assert.Equal(t, expected, res.Classes[0].Content())
+}
+
+// large returns a class of three long methods along with a token limit that fits only one of them.
+func large(t *testing.T) (domain.Class, int) {
+	t.Helper()
+	var code strings.Builder
+	code.WriteString("package a;\n\npublic class Big {\n")
+	for m := range 3 {
+		fmt.Fprintf(&code, "\n  /** Method number %d. */\n  public int second%d() {\n", m, m)
+		for i := range 20 {
+			fmt.Fprintf(&code, "    int value%d = %d * %d + this.hashCode();\n", i, i, m)
+		}
+		fmt.Fprintf(&code, "    return %d;\n  }\n", m)
+	}
+	code.WriteString("}\n")
+	tokens, err := stats.Tokens(code.String())
+	require.NoError(t, err)
+	return domain.NewInMemoryClass("Big", "Big.java", code.String()), tokens / 2
+}
+
+func TestFixClass_CountsContextAgainstTokenLimit(t *testing.T) {
+	var members strings.Builder
+	for i := range 50 {
+		fmt.Fprintf(&members, "  public int value%d() {\n    return %d;\n  }\n", i, i)
+	}
+	class := domain.NewInMemoryClass("Foo", "a/Foo.java",
+		"package a;\n\nclass Foo extends Base {\n"+strings.Repeat("  int field = 1;\n", 20)+"}\n")
+	example := domain.NewInMemoryClass("Near", "a/Near.java", "package a;\n\nclass Near {\n"+strings.Repeat("  int x = 1;\n", 8)+"}\n")
+	tokens, err := stats.Tokens(class.Content())
+	require.NoError(t, err)
+	needed, err := stats.Tokens(example.Content())
+	require.NoError(t, err)
+	limit := tokens + needed + 20
+	fixer := &rewriting{}
+	a := &agent{log: log.NewMock(), fixer: fixer}
+	set := settings{
+		mode:     "full",
+		limit:    limit,
+		examples: []domain.Class{example},
+		index: newIndex(
+			[]domain.Class{class, domain.NewInMemoryClass("Base", "a/Base.java", "package a;\n\nclass Base {\n"+members.String()+"}\n")},
+			log.NewMock(),
+		),
+	}
+
+	_, err = a.fixClass(context.Background(), class, nil, set)
+
+	require.NoError(t, err)
+	require.Len(t, fixer.jobs, 1)
+	job := fixer.jobs[0]
+	require.Len(t, job.Context, 1)
+	total := tokens
+	for _, c := range append(job.Context, job.Examples...) {
+		n, terr := stats.Tokens(c.Content())
+		require.NoError(t, terr)
+		total += n
+	}
+	assert.LessOrEqual(t, total, limit)
+	assert.Empty(t, job.Examples, "The example fits the limit only without the context")
+}
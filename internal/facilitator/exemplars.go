@@ -0,0 +1,75 @@
+package facilitator
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"github.com/cqfn/refrax/internal/domain"
+	"github.com/cqfn/refrax/internal/stats"
+)
+
+// exemplars returns the classes whose style the fixer follows. These are the example classes of the job,
+// or, if the job asks for "auto" examples, the classes the checks run before any change pass cleanly on.
+// The classes are copied, so that they keep their original content while the project is refactored.
+func (a *agent) exemplars(ws workspace, job *domain.Job, before *domain.Artifacts) ([]domain.Class, error) {
+	res := make([]domain.Class, 0)
+	for _, e := range job.Examples {
+		if e != nil {
+			res = append(res, domain.NewInMemoryClass(e.Name(), e.Path(), e.Content()))
+		}
+	}
+	if !automatic(job) {
+		return res, nil
+	}
+	classes, err := ws.classes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get classes to choose examples from: %w", err)
+	}
+	blamed := a.understandClasses(classes, before.Suggestions)
+	for _, c := range classes {
+		if _, ok := blamed[c]; ok {
+			continue
+		}
+		content, cerr := ws.content(c)
+		if cerr != nil {
+			return nil, fmt.Errorf("failed to read example class %s: %w", c.Path(), cerr)
+		}
+		res = append(res, domain.NewInMemoryClass(c.Name(), c.Path(), content))
+	}
+	a.log.Info("Chose %d example classes that pass the checks, %d classes fail them", len(res), len(blamed))
+	return res, nil
+}
+
+// automatic tells whether the job asks to choose the examples among the classes that pass the checks,
+// which it does with "auto" examples and no example classes.
+func automatic(job *domain.Job) bool {
+	auto, ok := job.Param("examples")
+	return ok && fmt.Sprintf("%v", auto) == "auto" && !slices.ContainsFunc(job.Examples, func(e domain.Class) bool { return e != nil })
+}
+
+// exemplify chooses the examples for the class: the ones of the same package first, as long as they fit
+// into the budget of tokens. The class itself is never an example of its own.
+func exemplify(class domain.Class, examples []domain.Class, budget int) []domain.Class {
+	res := make([]domain.Class, 0)
+	near := make([]domain.Class, 0, len(examples))
+	far := make([]domain.Class, 0, len(examples))
+	for _, e := range examples {
+		switch {
+		case e.Path() == class.Path():
+		case filepath.Dir(e.Path()) == filepath.Dir(class.Path()):
+			near = append(near, e)
+		default:
+			far = append(far, e)
+		}
+	}
+	for _, e := range append(near, far...) {
+		tokens, err := stats.Tokens(e.Content())
+		if err != nil || tokens > budget {
+			continue
+		}
+		res = append(res, e)
+		budget -= tokens
+	}
+	return res
+}
@@ -0,0 +1,95 @@
+package facilitator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cqfn/refrax/internal/domain"
+	"github.com/cqfn/refrax/internal/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExemplars_ChoosesClassesThatPassChecks(t *testing.T) {
+	paths := classes(t, "Foo.java", "Bar.java", "Baz.java")
+	require.NoError(t, os.WriteFile(paths[1], []byte("broken"), 0o600))
+	job := &domain.Job{
+		Descr: &domain.Description{Text: "refactor the project", Meta: map[string]any{"examples": "auto"}},
+	}
+	for _, p := range paths {
+		job.Classes = append(job.Classes, domain.NewFSClass(strings.TrimSuffix(filepath.Base(p), ".java"), p))
+	}
+	a := &agent{log: log.NewMock(), reviewer: &checks{paths: paths}}
+	ws := newWorkspace(job, false)
+	before, err := a.review(context.Background(), ws)
+	require.NoError(t, err)
+
+	res, err := a.exemplars(ws, job, before)
+
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	assert.Equal(t, paths[0], res[0].Path())
+	assert.Equal(t, paths[2], res[1].Path())
+	assert.Equal(t, "original", res[0].Content())
+}
+
+func TestExemplars_PrefersExamplesOfJob(t *testing.T) {
+	job := &domain.Job{
+		Descr:    &domain.Description{Text: "refactor the project", Meta: map[string]any{"examples": "auto"}},
+		Examples: []domain.Class{nil, domain.NewInMemoryClass("Order", "src/Order.java", "class Order {}")},
+	}
+	a := &agent{log: log.NewMock()}
+
+	res, err := a.exemplars(newWorkspace(job, false), job, &domain.Artifacts{})
+
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.Equal(t, "src/Order.java", res[0].Path())
+}
+
+func TestExemplify_PrefersSamePackageWithinBudget(t *testing.T) {
+	class := domain.NewInMemoryClass("Foo", "a/Foo.java", "class Foo {}")
+	examples := []domain.Class{
+		domain.NewInMemoryClass("Foo", "a/Foo.java", "class Foo {}"),
+		domain.NewInMemoryClass("Far", "b/Far.java", "class Far {}"),
+		domain.NewInMemoryClass("Huge", "a/Huge.java", "class Huge {\n"+strings.Repeat("  int field;\n", 100)+"}"),
+		domain.NewInMemoryClass("Near", "a/Near.java", "class Near {}"),
+	}
+
+	res := exemplify(class, examples, 100)
+
+	require.Len(t, res, 2)
+	assert.Equal(t, "a/Near.java", res[0].Path())
+	assert.Equal(t, "b/Far.java", res[1].Path())
+}
+
+func TestFacilitate_RunsChecksOnceForExamplesAndBaseline(t *testing.T) {
+	paths := classes(t, "Foo.java", "Bar.java")
+	job := &domain.Job{
+		Descr: &domain.Description{Text: "refactor the project", Meta: map[string]any{"examples": "auto"}},
+	}
+	for _, p := range paths {
+		job.Classes = append(job.Classes, domain.NewFSClass(strings.TrimSuffix(filepath.Base(p), ".java"), p))
+	}
+	rvwr := &counting{origin: &checks{paths: paths}}
+	a := &agent{log: log.NewMock(), critic: &reviewing{text: "nothing"}, reviewer: rvwr, attempts: 1}
+
+	_, err := a.facilitate(context.Background(), job)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, rvwr.runs)
+}
+
+// counting is a reviewer that counts the runs of the checks.
+type counting struct {
+	origin domain.Reviewer
+	runs   int
+}
+
+func (c *counting) Review(ctx context.Context, job *domain.Job) (*domain.Artifacts, error) {
+	c.runs++
+	return c.origin.Review(ctx, job)
+}
@@ -0,0 +1,246 @@
+package facilitator
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/cqfn/refrax/internal/domain"
+	"github.com/cqfn/refrax/internal/java"
+	"github.com/cqfn/refrax/internal/log"
+	"github.com/cqfn/refrax/internal/stats"
+)
+
+const (
+	// neighbors is the largest number of related classes attached to a job.
+	neighbors = 8
+	// sites is the largest number of call sites shown for one caller.
+	sites = 5
+)
+
+// index is a lightweight symbol index of the project: the packages, the types, the member declarations
+// and the imports of its classes. It finds the classes related to a class, so that the critic and the fixer
+// know its supertypes, the types it uses and the places that call it.
+type index struct {
+	entries []entry
+}
+
+// entry is an indexed class with its outline.
+type entry struct {
+	class domain.Class
+	file  *java.File
+}
+
+// neighbor is a class related to another one, closer relations have lower ranks.
+type neighbor struct {
+	entry entry
+	rank  int
+	// calls are the lines of the calls on receivers of the types of the class.
+	calls []int
+	// possible are the lines of the calls on receivers of unknown types or of the supertypes of the class.
+	possible []int
+}
+
+// newIndex indexes the classes, the classes that can't be parsed are left out.
+func newIndex(classes []domain.Class, logger log.Logger) *index {
+	res := &index{entries: make([]entry, 0, len(classes))}
+	for _, c := range classes {
+		file, err := java.Parse(c.Content())
+		if err != nil {
+			logger.Debug("Class %s is not indexed: %v", c.Path(), err)
+			continue
+		}
+		res.entries = append(res.entries, entry{class: c, file: file})
+	}
+	return res
+}
+
+// context returns the outlines of the classes related to the class: its supertypes, the types it uses
+// and the classes that call its methods, along with the lines of the calls. A call counts when its receiver
+// is declared with a type of the class, a call on a receiver of an unknown type or of a supertype is reported
+// as a possible call. The outlines fit into the budget of tokens: the outlines without members are taken,
+// closest neighbors first, while they fit, then the members are added while they fit. It returns the tokens taken.
+func (ix *index) context(class domain.Class, budget int) ([]domain.Class, int) {
+	if ix == nil || budget <= 0 {
+		return nil, 0
+	}
+	k := slices.IndexFunc(ix.entries, func(e entry) bool { return e.class.Path() == class.Path() })
+	if k < 0 {
+		return nil, 0
+	}
+	self := ix.entries[k]
+	supers := make(map[string]bool)
+	methods := make(map[string]bool)
+	types := make(map[string]bool)
+	for _, t := range self.file.Types {
+		types[t.Name] = true
+		for _, s := range t.Supertypes {
+			supers[s] = true
+		}
+		for _, m := range t.Methods {
+			name, _, _ := strings.Cut(m, "(")
+			methods[name] = true
+		}
+	}
+	related := make([]neighbor, 0)
+	for i, other := range ix.entries {
+		if i == k {
+			continue
+		}
+		n := neighbor{entry: other, rank: -1}
+		for _, t := range other.file.Types {
+			switch {
+			case supers[t.Name] && visible(self.file, other.file, t.Name):
+				n.rank = 0
+			case self.file.Refs[t.Name] && visible(self.file, other.file, t.Name) && n.rank != 0:
+				n.rank = 1
+			}
+		}
+		if callers(other.file, self.file) {
+			for m := range methods {
+				for _, c := range other.file.Calls[m] {
+					switch {
+					case types[c.Receiver]:
+						n.calls = append(n.calls, c.Line)
+					case c.Receiver == "" || supers[c.Receiver]:
+						n.possible = append(n.possible, c.Line)
+					}
+				}
+			}
+			n.calls = lines(n.calls)
+			n.possible = slices.DeleteFunc(lines(n.possible), func(l int) bool { return slices.Contains(n.calls, l) })
+			if n.rank < 0 && len(n.calls) > 0 {
+				n.rank = 2
+			} else if n.rank < 0 && len(n.possible) > 0 {
+				n.rank = 3
+			}
+		}
+		if n.rank >= 0 {
+			related = append(related, n)
+		}
+	}
+	sort.SliceStable(related, func(i, j int) bool {
+		if related[i].rank != related[j].rank {
+			return related[i].rank < related[j].rank
+		}
+		return related[i].entry.class.Path() < related[j].entry.class.Path()
+	})
+	kept := make([]neighbor, 0, min(len(related), neighbors))
+	bare := make([]int, 0, cap(kept))
+	used := 0
+	for _, n := range related[:min(len(related), neighbors)] {
+		tokens, err := stats.Tokens(n.outline(self, 0))
+		if err != nil || used+tokens > budget {
+			continue
+		}
+		kept = append(kept, n)
+		bare = append(bare, tokens)
+		used += tokens
+	}
+	res := make([]domain.Class, 0, len(kept))
+	for i, n := range kept {
+		text, tokens := n.fitted(self, budget-used+bare[i])
+		c := n.entry.class
+		res = append(res, domain.NewInMemoryClass(c.Name(), c.Path(), text))
+		used += tokens - bare[i]
+	}
+	return res, used
+}
+
+// fitted returns the outline of the neighbor with as many members as fit into the budget of tokens,
+// along with its tokens. The outline without members is expected to fit.
+func (n neighbor) fitted(self entry, budget int) (string, int) {
+	total := 0
+	for _, t := range n.entry.file.Types {
+		total += len(t.Members)
+	}
+	text := n.outline(self, 0)
+	tokens, _ := stats.Tokens(text)
+	lo, hi := 1, total
+	for lo <= hi {
+		keep := (lo + hi) / 2
+		candidate := n.outline(self, keep)
+		count, err := stats.Tokens(candidate)
+		if err != nil || count > budget {
+			hi = keep - 1
+			continue
+		}
+		text, tokens = candidate, count
+		lo = keep + 1
+	}
+	return text, tokens
+}
+
+// outline lists the declarations of the neighbor, at most keep members of them, and the lines where it calls
+// or may call the class.
+func (n neighbor) outline(self entry, keep int) string {
+	var res strings.Builder
+	if n.entry.file.Package != "" {
+		fmt.Fprintf(&res, "package %s;\n", n.entry.file.Package)
+	}
+	for _, t := range n.entry.file.Types {
+		fmt.Fprintf(&res, "%s %s", t.Kind, t.Name)
+		if len(t.Supertypes) > 0 {
+			fmt.Fprintf(&res, ", supertypes: %s", strings.Join(t.Supertypes, ", "))
+		}
+		res.WriteString("\n")
+		for _, m := range t.Members[:min(len(t.Members), keep)] {
+			fmt.Fprintf(&res, "  %s\n", m)
+		}
+		if len(t.Members) > keep {
+			fmt.Fprintf(&res, "  and %d more members\n", len(t.Members)-keep)
+		}
+		keep = max(keep-len(t.Members), 0)
+	}
+	src := strings.Split(n.entry.class.Content(), "\n")
+	for _, group := range []struct {
+		title string
+		lines []int
+	}{
+		{"Calls of %s:\n", n.calls},
+		{"Possible calls of %s:\n", n.possible},
+	} {
+		if len(group.lines) == 0 {
+			continue
+		}
+		fmt.Fprintf(&res, group.title, self.class.Name())
+		for _, l := range group.lines[:min(len(group.lines), sites)] {
+			fmt.Fprintf(&res, "  line %d: %s\n", l, strings.TrimSpace(src[l-1]))
+		}
+		if len(group.lines) > sites {
+			fmt.Fprintf(&res, "  and %d more\n", len(group.lines)-sites)
+		}
+	}
+	return strings.TrimSuffix(res.String(), "\n")
+}
+
+// lines sorts the line numbers and drops the duplicates.
+func lines(nums []int) []int {
+	slices.Sort(nums)
+	return slices.Compact(nums)
+}
+
+// callers tells whether the file refers to any type of the class, so that its calls may be calls of the class.
+func callers(file, class *java.File) bool {
+	for _, t := range class.Types {
+		if file.Refs[t.Name] && visible(file, class, t.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// visible tells whether the file can refer to the type of the other file by its simple name:
+// they share the package or the file imports the type or its package.
+func visible(file, other *java.File, name string) bool {
+	if file.Package == other.Package {
+		return true
+	}
+	for _, imp := range file.Imports {
+		if imp == other.Package+"."+name || imp == other.Package+".*" {
+			return true
+		}
+	}
+	return false
+}
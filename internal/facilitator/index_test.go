@@ -0,0 +1,93 @@
+package facilitator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cqfn/refrax/internal/domain"
+	"github.com/cqfn/refrax/internal/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndex_FindsSupertypesUsedTypesAndCallers(t *testing.T) {
+	foo := domain.NewInMemoryClass("Foo", "a/Foo.java",
+		"package a;\n\npublic class Foo extends Base<String> implements java.io.Serializable {\n"+
+			"  private final Bar bar = new Bar();\n\n  public int size(String name, int unused) {\n    return name.length();\n  }\n}\n")
+	ix := newIndex([]domain.Class{
+		foo,
+		domain.NewInMemoryClass("Base", "a/Base.java", "package a;\n\npublic abstract class Base<T> {\n  protected abstract T value();\n}\n"),
+		domain.NewInMemoryClass("Bar", "a/Bar.java", "package a;\n\nclass Bar {\n  private int secret;\n  int count() {\n    return 0;\n  }\n}\n"),
+		domain.NewInMemoryClass("Bar", "c/Bar.java", "package c;\n\npublic class Bar {\n}\n"),
+		domain.NewInMemoryClass("Caller", "b/Caller.java",
+			"package b;\n\nimport a.Foo;\n\nclass Caller {\n  int run(Foo foo) {\n    return foo.size(\"x\", 0);\n  }\n}\n"),
+		domain.NewInMemoryClass("Other", "b/Other.java", "package b;\n\nclass Other {\n  int run(java.util.List<String> l) {\n    return l.size();\n  }\n}\n"),
+		domain.NewInMemoryClass("Broken", "b/Broken.java", "package b;\n\nclass Broken {\n"),
+	}, log.NewMock())
+
+	related, _ := ix.context(foo, 1000)
+
+	require.Len(t, related, 3)
+	assert.Equal(t, "a/Base.java", related[0].Path())
+	assert.Equal(t, "package a;\nclass Base\n  protected abstract T value()", related[0].Content())
+	assert.Equal(t, "a/Bar.java", related[1].Path())
+	assert.Equal(t, "package a;\nclass Bar\n  int count()", related[1].Content())
+	assert.Equal(t, "b/Caller.java", related[2].Path())
+	assert.Contains(t, related[2].Content(), "Calls of Foo:\n  line 7: return foo.size(\"x\", 0);")
+}
+
+func TestIndex_TellsCallsOfClassFromCallsOfOtherTypes(t *testing.T) {
+	foo := domain.NewInMemoryClass("Foo", "a/Foo.java", "package a;\n\npublic class Foo {\n  public int size() {\n    return 0;\n  }\n}\n")
+	ix := newIndex([]domain.Class{
+		foo,
+		domain.NewInMemoryClass("Caller", "a/Caller.java",
+			"package a;\n\nclass Caller {\n  int run(Foo foo, java.util.List<String> l) {\n"+
+				"    return foo.size() + l.size() + make().size();\n  }\n  int other(java.util.List<Foo> l) {\n    return l.size();\n  }\n}\n"),
+		domain.NewInMemoryClass("Guess", "a/Guess.java",
+			"package a;\n\nclass Guess {\n  int run() {\n    Foo.class.getName();\n    return make().size();\n  }\n}\n"),
+		domain.NewInMemoryClass("Lists", "a/Lists.java",
+			"package a;\n\nclass Lists {\n  Foo foo;\n  int run(java.util.List<String> l) {\n    return l.size();\n  }\n}\n"),
+	}, log.NewMock())
+
+	related, _ := ix.context(foo, 1000)
+
+	require.Len(t, related, 2, "A class that only calls methods of the same name on other types is not related")
+	assert.Equal(t, "a/Caller.java", related[0].Path())
+	assert.Contains(t, related[0].Content(), "Calls of Foo:\n  line 5: return foo.size() + l.size() + make().size();")
+	assert.NotContains(t, related[0].Content(), "line 8")
+	assert.Equal(t, "a/Guess.java", related[1].Path())
+	assert.Contains(t, related[1].Content(), "Possible calls of Foo:\n  line 6: return make().size();")
+}
+
+func TestIndex_TrimsContextToBudget(t *testing.T) {
+	foo := domain.NewInMemoryClass("Foo", "a/Foo.java", "package a;\n\nclass Foo extends Base {\n  Bar bar;\n}\n")
+	var members strings.Builder
+	for i := range 50 {
+		fmt.Fprintf(&members, "  public int value%d() {\n    return %d;\n  }\n", i, i)
+	}
+	ix := newIndex([]domain.Class{
+		foo,
+		domain.NewInMemoryClass("Base", "a/Base.java", "package a;\n\nclass Base {\n"+members.String()+"}\n"),
+		domain.NewInMemoryClass("Bar", "a/Bar.java", "package a;\n\nclass Bar {\n  public int count() {\n    return 0;\n  }\n}\n"),
+	}, log.NewMock())
+
+	related, used := ix.context(foo, 100)
+
+	require.Len(t, related, 2)
+	assert.Contains(t, related[0].Content(), "more members")
+	assert.Equal(t, "package a;\nclass Bar\n  public int count()", related[1].Content())
+	assert.LessOrEqual(t, used, 100)
+	related, used = ix.context(foo, 3)
+	assert.Empty(t, related, "Outlines that don't fit even without members should be left out")
+	assert.Zero(t, used)
+}
+
+func TestIndex_ReturnsNothingForUnknownClass(t *testing.T) {
+	ix := newIndex([]domain.Class{}, log.NewMock())
+
+	related, _ := ix.context(domain.NewInMemoryClass("Foo", "Foo.java", "class Foo {}"), 1000)
+	assert.Empty(t, related)
+	related, _ = (*index)(nil).context(domain.NewInMemoryClass("Foo", "Foo.java", "class Foo {}"), 1000)
+	assert.Nil(t, related)
+}
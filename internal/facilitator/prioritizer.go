@@ -0,0 +1,308 @@
+package facilitator
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/cqfn/refrax/internal/brain"
+	"github.com/cqfn/refrax/internal/domain"
+	"github.com/cqfn/refrax/internal/log"
+	"github.com/cqfn/refrax/internal/prompts"
+)
+
+// similarity is the share of common words two suggestions need to fall into the same cluster
+// when their category doesn't tell that they are about the same thing.
+const similarity = 0.3
+
+// prioritizer chooses the suggestions to apply in a refactoring round among all the suggestions of the critic.
+type prioritizer interface {
+	prioritize(ctx context.Context, improvements []critique) ([]critique, error)
+}
+
+// weights tell how much each property of a cluster of suggestions matters when the clusters are ranked.
+type weights struct {
+	severity  float64
+	frequency float64
+	spread    float64
+}
+
+// clustering is a prioritizer that groups similar suggestions by their category and words,
+// ranks the groups and chooses the best one. It works locally, without asking the brain.
+type clustering struct {
+	weights weights
+	log     log.Logger
+}
+
+// asking is a prioritizer that asks the brain to group the suggestions and to choose the largest group.
+type asking struct {
+	brain brain.Brain
+	log   log.Logger
+}
+
+// cluster is a group of similar suggestions, possibly for different classes.
+type cluster struct {
+	category string
+	words    map[string]bool
+	members  []member
+}
+
+// member is a suggestion of a cluster along with its class.
+type member struct {
+	class      domain.Class
+	suggestion domain.Suggestion
+}
+
+// newPrioritizer creates the prioritizer the job asks for with its "prioritizer" and "weights" parameters.
+// The local clustering is the default one.
+func newPrioritizer(job *domain.Job, ai brain.Brain, logger log.Logger) (prioritizer, error) {
+	name := "local"
+	if p, ok := job.Param("prioritizer"); ok && fmt.Sprintf("%v", p) != "" {
+		name = fmt.Sprintf("%v", p)
+	}
+	switch name {
+	case "local":
+		w := weights{severity: 1, frequency: 1, spread: 1}
+		if p, ok := job.Param("weights"); ok {
+			parsed, err := parseWeights(fmt.Sprintf("%v", p), w)
+			if err != nil {
+				return nil, err
+			}
+			w = parsed
+		}
+		return &clustering{weights: w, log: logger}, nil
+	case "llm":
+		return &asking{brain: ai, log: logger}, nil
+	default:
+		return nil, fmt.Errorf("unknown prioritizer %q, expected one of: local, llm", name)
+	}
+}
+
+// parseWeights reads the weights in the "severity=2,frequency=1,spread=0.5" format,
+// the weights that are not mentioned keep their defaults.
+func parseWeights(text string, defaults weights) (weights, error) {
+	res := defaults
+	for _, pair := range strings.Split(text, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return res, fmt.Errorf("weight %q has no value, expected name=value", pair)
+		}
+		w, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || w < 0 {
+			return res, fmt.Errorf("weight %q must be a non-negative number", pair)
+		}
+		switch strings.TrimSpace(name) {
+		case "severity":
+			res.severity = w
+		case "frequency":
+			res.frequency = w
+		case "spread":
+			res.spread = w
+		default:
+			return res, fmt.Errorf("unknown weight %q, expected one of: severity, frequency, spread", name)
+		}
+	}
+	return res, nil
+}
+
+// prioritize clusters the suggestions and returns the ones of the best cluster, grouped by class.
+func (c *clustering) prioritize(_ context.Context, improvements []critique) ([]critique, error) {
+	sorted := slices.Clone(improvements)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].class.Path() < sorted[j].class.Path() })
+	clusters := make([]*cluster, 0)
+	for _, imp := range sorted {
+		for _, s := range imp.suggestions {
+			m := member{class: imp.class, suggestion: s}
+			if best := closest(clusters, s); best != nil {
+				best.members = append(best.members, m)
+			} else {
+				clusters = append(clusters, &cluster{category: s.Category, words: words(s.Text), members: []member{m}})
+			}
+		}
+	}
+	if len(clusters) == 0 {
+		return []critique{}, nil
+	}
+	largest, classes := 0, 0
+	for _, cl := range clusters {
+		largest = max(largest, len(cl.members))
+	}
+	for _, imp := range sorted {
+		if len(imp.suggestions) > 0 {
+			classes++
+		}
+	}
+	scores := make(map[*cluster]float64, len(clusters))
+	for _, cl := range clusters {
+		scores[cl] = c.weights.severity*cl.severity() +
+			c.weights.frequency*float64(len(cl.members))/float64(largest) +
+			c.weights.spread*float64(cl.classes())/float64(classes)
+	}
+	sort.SliceStable(clusters, func(i, j int) bool {
+		if scores[clusters[i]] != scores[clusters[j]] {
+			return scores[clusters[i]] > scores[clusters[j]]
+		}
+		return len(clusters[i].members) > len(clusters[j].members)
+	})
+	best := clusters[0]
+	c.log.Info(
+		"Chose %d suggestions of category %q out of %d clusters (score %.2f): %s",
+		len(best.members), best.category, len(clusters), scores[best], best.members[0].suggestion.Text,
+	)
+	res := make([]critique, 0)
+	for _, m := range best.members {
+		if len(res) > 0 && res[len(res)-1].class.Path() == m.class.Path() {
+			res[len(res)-1].suggestions = append(res[len(res)-1].suggestions, m.suggestion)
+			continue
+		}
+		res = append(res, critique{class: m.class, suggestions: []domain.Suggestion{m.suggestion}})
+	}
+	return res, nil
+}
+
+// closest returns the cluster the suggestion belongs to, or nil if it needs a new one.
+// Suggestions of the same specific category share a cluster, the others need similar words.
+func closest(clusters []*cluster, s domain.Suggestion) *cluster {
+	ws := words(s.Text)
+	var best *cluster
+	score := 0.0
+	for _, cl := range clusters {
+		if s.Category != cl.category && s.Category != "" && cl.category != "" {
+			continue
+		}
+		if s.Category == cl.category && s.Category != "" && s.Category != "other" {
+			return cl
+		}
+		if sim := jaccard(ws, cl.words); sim >= similarity && sim > score {
+			best, score = cl, sim
+		}
+	}
+	return best
+}
+
+// severity is the average severity of the suggestions of the cluster, from 0 to 1.
+// Suggestions without a severity count as medium ones.
+func (cl *cluster) severity() float64 {
+	levels := map[string]float64{"low": 1, "medium": 2, "high": 3}
+	total := 0.0
+	for _, m := range cl.members {
+		level, ok := levels[m.suggestion.Severity]
+		if !ok {
+			level = levels["medium"]
+		}
+		total += level
+	}
+	return total / float64(len(cl.members)) / levels["high"]
+}
+
+// classes is the number of distinct classes the suggestions of the cluster are for.
+func (cl *cluster) classes() int {
+	seen := make(map[string]bool)
+	for _, m := range cl.members {
+		seen[m.class.Path()] = true
+	}
+	return len(seen)
+}
+
+// words splits the text into a set of lowercase words.
+func words(text string) map[string]bool {
+	res := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		res[w] = true
+	}
+	return res
+}
+
+// jaccard is the share of common words among all the words of two sets.
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+	common := 0
+	for w := range a {
+		if b[w] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(a)+len(b)-common)
+}
+
+// prioritize asks the brain to group the suggestions and then to choose the largest group.
+func (a *asking) prioritize(ctx context.Context, improvements []critique) ([]critique, error) {
+	a.log.Info("Grouping all suggestions...")
+	all := make([]domain.Suggestion, 0, len(improvements))
+	for _, imp := range improvements {
+		all = append(all, imp.suggestions...)
+	}
+	type groupPromptData struct {
+		Suggestions []domain.Suggestion
+	}
+	prompt := prompts.User{
+		Data: groupPromptData{
+			Suggestions: all,
+		},
+		Name: "facilitator/group.md.tmpl",
+	}
+	important, err := a.brain.Ask(ctx, prompt.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to ask the brain to group suggestions: %w", err)
+	}
+	type choosePromoptData struct {
+		Groupped string
+	}
+	prompt = prompts.User{
+		Data: choosePromoptData{
+			Groupped: important,
+		},
+		Name: "facilitator/choose.md.tmpl",
+	}
+	a.log.Info("Choosing the most important suggestions...")
+	important, err = a.brain.Ask(ctx, prompt.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to ask brain for most frequent suggestion: %w", err)
+	}
+	classSuggestions := make(map[string][]string, 0)
+	for s := range strings.SplitSeq(strings.ReplaceAll(important, "\r\n", "\n"), "\n") {
+		a.log.Info("Suggestion to consider: %s", s)
+		className, classSuggestion, ok := strings.Cut(s, ":")
+		if !ok {
+			a.log.Warn("Can't find a delimiter ':'")
+			continue
+		}
+		className = strings.TrimSpace(className)
+		classSuggestion = strings.TrimSpace(classSuggestion)
+		if className == "" || classSuggestion == "" {
+			a.log.Warn("Skipping suggestion without class name or text: %s", s)
+			continue
+		}
+		classSuggestions[className] = append(classSuggestions[className], classSuggestion)
+	}
+	a.log.Info("Received %d suggestions from brain", len(classSuggestions))
+	ires := make([]critique, 0)
+	for k, v := range classSuggestions {
+		class, err := find(improvements, k)
+		if err != nil {
+			a.log.Warn("Class %s not found in improvements, skipping: %v", k, err)
+			continue
+		}
+		var suggetions []domain.Suggestion
+		for _, s := range v {
+			suggetions = append(suggetions, original(improvements, class.Path(), s))
+		}
+		ires = append(ires, critique{
+			class:       class,
+			suggestions: suggetions,
+		})
+	}
+	return ires, nil
+}
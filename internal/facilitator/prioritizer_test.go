@@ -0,0 +1,132 @@
+package facilitator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cqfn/refrax/internal/brain"
+	"github.com/cqfn/refrax/internal/domain"
+	"github.com/cqfn/refrax/internal/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClustering_ChoosesLargestClusterOfCategory(t *testing.T) {
+	foo := domain.NewInMemoryClass("Foo", "Foo.java", "class Foo {}")
+	bar := domain.NewInMemoryClass("Bar", "Bar.java", "class Bar {}")
+	improvements := []critique{
+		{class: foo, suggestions: []domain.Suggestion{
+			{ClassPath: "Foo.java", Text: "Inline variable x", Category: "inline", Severity: "low"},
+			{ClassPath: "Foo.java", Text: "Split method run", Category: "long-method", Severity: "low"},
+		}},
+		{class: bar, suggestions: []domain.Suggestion{
+			{ClassPath: "Bar.java", Text: "Inline variable count", Category: "inline", Severity: "low"},
+		}},
+	}
+	p := &clustering{weights: weights{severity: 1, frequency: 1, spread: 1}, log: log.NewMock()}
+
+	chosen, err := p.prioritize(context.Background(), improvements)
+
+	require.NoError(t, err)
+	require.Len(t, chosen, 2)
+	assert.Equal(t, "Bar.java", chosen[0].class.Path())
+	assert.Equal(t, "Inline variable count", chosen[0].suggestions[0].Text)
+	assert.Equal(t, "Foo.java", chosen[1].class.Path())
+	assert.Equal(t, "Inline variable x", chosen[1].suggestions[0].Text)
+}
+
+func TestClustering_PrefersSevereSuggestionsWithSeverityWeight(t *testing.T) {
+	foo := domain.NewInMemoryClass("Foo", "Foo.java", "class Foo {}")
+	bar := domain.NewInMemoryClass("Bar", "Bar.java", "class Bar {}")
+	improvements := []critique{
+		{class: foo, suggestions: []domain.Suggestion{
+			{ClassPath: "Foo.java", Text: "Fix typo in comment", Category: "comments", Severity: "low"},
+			{ClassPath: "Foo.java", Text: "Simplify nested loops", Category: "complexity", Severity: "high"},
+		}},
+		{class: bar, suggestions: []domain.Suggestion{
+			{ClassPath: "Bar.java", Text: "Fix grammar in comment", Category: "comments", Severity: "low"},
+		}},
+	}
+	p := &clustering{weights: weights{severity: 10, frequency: 1, spread: 1}, log: log.NewMock()}
+
+	chosen, err := p.prioritize(context.Background(), improvements)
+
+	require.NoError(t, err)
+	require.Len(t, chosen, 1)
+	assert.Equal(t, "Simplify nested loops", chosen[0].suggestions[0].Text)
+}
+
+func TestClustering_GroupsPlainSuggestionsBySimilarWords(t *testing.T) {
+	foo := domain.NewInMemoryClass("Foo", "Foo.java", "class Foo {}")
+	improvements := []critique{
+		{class: foo, suggestions: []domain.Suggestion{
+			*domain.NewSuggestion("Remove redundant comment in method run", "Foo.java"),
+			*domain.NewSuggestion("Extract constant for timeout", "Foo.java"),
+			*domain.NewSuggestion("Remove redundant comment in method stop", "Foo.java"),
+		}},
+	}
+	p := &clustering{weights: weights{severity: 1, frequency: 1, spread: 1}, log: log.NewMock()}
+
+	chosen, err := p.prioritize(context.Background(), improvements)
+
+	require.NoError(t, err)
+	require.Len(t, chosen, 1)
+	assert.Len(t, chosen[0].suggestions, 2)
+}
+
+func TestAsking_KeepsTextWithColons(t *testing.T) {
+	foo := domain.NewInMemoryClass("Foo", "Foo.java", "class Foo {}")
+	improvements := []critique{{class: foo, suggestions: []domain.Suggestion{
+		{ClassPath: "Foo.java", Text: "Replace the loop with a stream: it is shorter", Category: "complexity"},
+	}}}
+	p := &asking{brain: &answering{answer: "Foo.java: Replace the loop with a stream: it is shorter"}, log: log.NewMock()}
+
+	chosen, err := p.prioritize(context.Background(), improvements)
+
+	require.NoError(t, err)
+	require.Len(t, chosen, 1)
+	assert.Equal(t, improvements[0].suggestions, chosen[0].suggestions)
+}
+
+func TestNewPrioritizer_ReadsWeightsOfJob(t *testing.T) {
+	job := &domain.Job{Descr: &domain.Description{Meta: map[string]any{"weights": "severity=3, spread=0"}}}
+
+	p, err := newPrioritizer(job, brain.NewMock(), log.NewMock())
+
+	require.NoError(t, err)
+	assert.Equal(t, weights{severity: 3, frequency: 1, spread: 0}, p.(*clustering).weights)
+}
+
+func TestNewPrioritizer_ChoosesBrainOnRequest(t *testing.T) {
+	job := &domain.Job{Descr: &domain.Description{Meta: map[string]any{"prioritizer": "llm"}}}
+
+	p, err := newPrioritizer(job, brain.NewMock(), log.NewMock())
+
+	require.NoError(t, err)
+	assert.IsType(t, &asking{}, p)
+}
+
+func TestNewPrioritizer_FailsOnUnknownWeight(t *testing.T) {
+	job := &domain.Job{Descr: &domain.Description{Meta: map[string]any{"weights": "size=2"}}}
+
+	_, err := newPrioritizer(job, brain.NewMock(), log.NewMock())
+
+	assert.Error(t, err)
+}
+
+func TestNewPrioritizer_FailsOnUnknownStrategy(t *testing.T) {
+	job := &domain.Job{Descr: &domain.Description{Meta: map[string]any{"prioritizer": "random"}}}
+
+	_, err := newPrioritizer(job, brain.NewMock(), log.NewMock())
+
+	assert.Error(t, err)
+}
+
+// answering is a brain that always gives the same answer.
+type answering struct {
+	answer string
+}
+
+func (a *answering) Ask(_ context.Context, _ string) (string, error) {
+	return a.answer, nil
+}
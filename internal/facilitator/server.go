@@ -11,12 +11,15 @@ import (
 	"github.com/cqfn/refrax/internal/protocol"
 )
 
+// Skill is the ID of the skill the facilitator agent declares in its agent card.
+const Skill = "facilitate-discussion"
+
 // A2AFacilitator facilitates communication between the critic and fixer agents.
 type A2AFacilitator struct {
 	server   protocol.Server
 	log      log.Logger
 	port     int
-	original domain.Facilitator
+	original *agent
 }
 
 // NewFacilitator creates a new instance of Facilitator to manage communication between agents.
@@ -36,20 +39,45 @@ func NewFacilitator(ai brain.Brain, critic domain.Critic, fixer domain.Fixer, re
 			reviewer: reviewer,
 			frounds:  3,
 			attempts: attempts,
+			slots:    make(chan struct{}, fanout),
 		},
 	}
 	server.MsgHandler(facilitator.think)
 	return facilitator
 }
 
+// OnRound sets the hook that is called after every successful refactoring round,
+// e.g. to commit the changes of the round. An error of the hook stops the refactoring.
+func (f *A2AFacilitator) OnRound(hook func(domain.Round) error) {
+	f.original.rounds = hook
+}
+
+// Concurrency sets the number of requests sent to the critic or the fixer at once, usually the maximum number
+// of concurrent requests of the brains. The other requests wait for their turn in the facilitator,
+// so they don't run into the timeout of the client. A non-positive number keeps the default.
+func (f *A2AFacilitator) Concurrency(n int) {
+	if n > 0 {
+		f.original.slots = make(chan struct{}, n)
+	}
+}
+
+// Remote makes the facilitator keep the classes in memory instead of writing them to their files,
+// since the clients of a standalone facilitator may run on other machines. The clients apply
+// the refactored classes themselves.
+func (f *A2AFacilitator) Remote() {
+	f.original.remote = true
+}
+
 // Refactor sends a refactoring request to the facilitator server and returns the refactored classes.
 func (f *A2AFacilitator) Refactor(job *domain.Job) (*domain.Artifacts, error) {
 	client := protocol.NewClient(fmt.Sprintf("http://localhost:%d", f.port))
-	resp, err := client.SendMessage(job.Marshal())
+	answer, err := protocol.Await(client.StreamMessage(job.Marshal()), func(text string) {
+		f.log.Info("Progress: %s", text)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to send refactoring request: %w", err)
 	}
-	return domain.UnmarshalArtifacts(resp.Result.(*protocol.Message))
+	return domain.UnmarshalArtifacts(answer)
 }
 
 // ListenAndServe starts the facilitator server and prepares it for handling requests.
@@ -87,16 +115,16 @@ func (f *A2AFacilitator) think(ctx context.Context, m *protocol.Message) (*proto
 	case <-ctx.Done():
 		return nil, fmt.Errorf("context canceled: %w", ctx.Err())
 	default:
-		return f.thinkLong(m)
+		return f.thinkLong(ctx, m)
 	}
 }
 
-func (f *A2AFacilitator) thinkLong(m *protocol.Message) (*protocol.Message, error) {
+func (f *A2AFacilitator) thinkLong(ctx context.Context, m *protocol.Message) (*protocol.Message, error) {
 	job, err := domain.UnmarshalJob(m)
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal job: %w", err)
 	}
-	resp, err := f.original.Refactor(job)
+	resp, err := f.original.facilitate(ctx, job)
 	if err != nil {
 		return nil, fmt.Errorf("failed to refactor task: %w", err)
 	}
@@ -105,10 +133,12 @@ func (f *A2AFacilitator) thinkLong(m *protocol.Message) (*protocol.Message, erro
 }
 
 func agentCard(port int) *protocol.AgentCard {
+	supported := true
 	return protocol.NewAgentCard().
 		WithName("Facilitator Agent").
 		WithDescription("An agent that facilitates talk between critic and fixer").
 		WithURL(fmt.Sprintf("http://localhost:%d", port)).
 		WithVersion("0.0.1").
-		AddSkill("facilitate-discussion", "Refactor Java Projects", "Facilitate discussion on code refactoring")
+		WithCapabilities(protocol.AgentCapabilities{Streaming: &supported, PushNotifications: &supported}).
+		AddSkill(Skill, "Refactor Java Projects", "Facilitate discussion on code refactoring")
 }
@@ -0,0 +1,79 @@
+package facilitator
+
+import (
+	"fmt"
+
+	"github.com/cqfn/refrax/internal/domain"
+)
+
+// snapshot remembers the content of the classes before the fixer changed them,
+// so that the changes that break the build can be reverted.
+type snapshot struct {
+	ws       workspace
+	order    []string
+	classes  map[string]domain.Class
+	original map[string]string
+	current  map[string]string
+}
+
+// newSnapshot creates an empty snapshot of the classes in the workspace.
+func newSnapshot(ws workspace) *snapshot {
+	return &snapshot{
+		ws:       ws,
+		order:    make([]string, 0),
+		classes:  make(map[string]domain.Class),
+		original: make(map[string]string),
+		current:  make(map[string]string),
+	}
+}
+
+// save stores the new content of the class in the workspace.
+// The content the class had before its first change is kept in the snapshot.
+func (s *snapshot) save(class domain.Class, content string) error {
+	path := class.Path()
+	if _, ok := s.original[path]; !ok {
+		before, err := s.ws.content(class)
+		if err != nil {
+			return fmt.Errorf("failed to remember class %s: %w", path, err)
+		}
+		s.order = append(s.order, path)
+		s.classes[path] = class
+		s.original[path] = before
+	}
+	if err := s.ws.save(class, content); err != nil {
+		return err
+	}
+	s.current[path] = content
+	return nil
+}
+
+// revert brings back the original content of the classes, their latest changes are kept in the snapshot.
+func (s *snapshot) revert(paths ...string) error {
+	for _, path := range paths {
+		if err := s.ws.save(s.classes[path], s.original[path]); err != nil {
+			return fmt.Errorf("failed to revert class %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// restore brings back the latest changes of the classes.
+func (s *snapshot) restore(paths ...string) error {
+	for _, path := range paths {
+		if err := s.ws.save(s.classes[path], s.current[path]); err != nil {
+			return fmt.Errorf("failed to restore class %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// paths returns the paths of the changed classes in the order they were changed.
+func (s *snapshot) paths() []string {
+	return s.order
+}
+
+// class returns the changed class with its latest content.
+func (s *snapshot) class(path string) domain.Class {
+	c := s.classes[path]
+	return domain.NewInMemoryClass(c.Name(), path, s.current[path])
+}
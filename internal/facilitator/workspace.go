@@ -0,0 +1,185 @@
+package facilitator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cqfn/refrax/internal/domain"
+)
+
+// workspace is where the facilitator keeps the classes it refactors.
+type workspace interface {
+	// classes returns the classes to refactor.
+	classes() ([]domain.Class, error)
+
+	// content returns the current content of the class.
+	content(class domain.Class) (string, error)
+
+	// save stores the new content of the class.
+	save(class domain.Class, content string) error
+
+	// checkout prepares the directory for the checks of the reviewer, the empty one stands for the current one.
+	// The returned function removes the directory.
+	checkout() (string, func(), error)
+
+	// changed returns the final versions of the refactored classes, given the ones the fixer returned.
+	changed(fixed []domain.Class) ([]domain.Class, error)
+}
+
+// disk is a workspace that writes the classes right into their files.
+type disk struct {
+	all []domain.Class
+}
+
+// overlay is a workspace that keeps the classes in memory and leaves the files intact.
+// The reviewer checks a temporary copy of the project with the changes.
+type overlay struct {
+	project *domain.OverlayProject
+}
+
+// memory is a workspace that keeps the classes in memory when the facilitator serves remote clients,
+// since their files are on another machine. The reviewer checks a temporary directory with the classes alone.
+type memory struct {
+	overlay
+}
+
+// newWorkspace creates the workspace for the job: the memory one if the facilitator can't reach the files,
+// an overlay for dry runs, the disk otherwise.
+func newWorkspace(job *domain.Job, remote bool) workspace {
+	if remote {
+		return &memory{overlay{project: domain.NewOverlayProject("", domain.NewInMemory(job.Classes...))}}
+	}
+	if dry, ok := job.Param("dry-run"); ok && fmt.Sprintf("%v", dry) == "true" {
+		return &overlay{project: domain.NewOverlayProject(job.Dir(), domain.NewInMemory(job.Classes...))}
+	}
+	return &disk{all: job.Classes}
+}
+
+func (d *disk) classes() ([]domain.Class, error) {
+	return d.all, nil
+}
+
+func (d *disk) content(class domain.Class) (string, error) {
+	data, err := os.ReadFile(class.Path())
+	if err != nil {
+		return "", fmt.Errorf("failed to read class %s: %w", class.Path(), err)
+	}
+	return string(data), nil
+}
+
+func (d *disk) save(class domain.Class, content string) error {
+	return domain.NewFSClass(class.Name(), class.Path()).SetContent(content)
+}
+
+func (d *disk) checkout() (string, func(), error) {
+	return "", func() {}, nil
+}
+
+func (d *disk) changed(fixed []domain.Class) ([]domain.Class, error) {
+	return fixed, nil
+}
+
+func (o *overlay) classes() ([]domain.Class, error) {
+	return o.project.Classes()
+}
+
+func (o *overlay) content(class domain.Class) (string, error) {
+	c, err := o.find(class)
+	if err != nil {
+		return "", err
+	}
+	return c.Content(), nil
+}
+
+func (o *overlay) save(class domain.Class, content string) error {
+	c, err := o.find(class)
+	if err != nil {
+		return err
+	}
+	return c.SetContent(content)
+}
+
+func (o *overlay) checkout() (string, func(), error) {
+	dir, err := os.MkdirTemp("", "refrax-dry-run-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create a temporary directory: %w", err)
+	}
+	cleanup := func() { _ = os.RemoveAll(dir) }
+	if err = o.project.Materialize(dir); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return dir, cleanup, nil
+}
+
+func (o *overlay) changed(_ []domain.Class) ([]domain.Class, error) {
+	return o.project.Changed()
+}
+
+// find returns the class of the overlay project with the same path.
+func (o *overlay) find(class domain.Class) (domain.Class, error) {
+	all, err := o.project.Classes()
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range all {
+		if c.Path() == class.Path() {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("class %s is not part of the project", class.Path())
+}
+
+func (m *memory) checkout() (string, func(), error) {
+	dir, err := os.MkdirTemp("", "refrax-remote-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create a temporary directory: %w", err)
+	}
+	cleanup := func() { _ = os.RemoveAll(dir) }
+	all, err := m.project.Classes()
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	paths := make([]string, 0, len(all))
+	for _, c := range all {
+		paths = append(paths, c.Path())
+	}
+	root := common(paths)
+	for _, c := range all {
+		rel, rerr := filepath.Rel(root, filepath.Clean(c.Path()))
+		if rerr != nil || strings.HasPrefix(rel, "..") {
+			rel = filepath.Base(c.Path())
+		}
+		target := filepath.Join(dir, rel)
+		if err = os.MkdirAll(filepath.Dir(target), 0o750); err == nil {
+			err = os.WriteFile(target, []byte(c.Content()), 0o600)
+		}
+		if err != nil {
+			cleanup()
+			return "", nil, fmt.Errorf("failed to write class %s to %s: %w", c.Path(), dir, err)
+		}
+	}
+	return dir, cleanup, nil
+}
+
+// common returns the deepest directory that contains all the paths.
+func common(paths []string) string {
+	if len(paths) == 0 {
+		return ""
+	}
+	res := filepath.Dir(filepath.Clean(paths[0]))
+	for _, p := range paths[1:] {
+		dir := filepath.Dir(filepath.Clean(p))
+		for res != dir && !strings.HasPrefix(dir, res+string(filepath.Separator)) && res != string(filepath.Separator) {
+			parent := filepath.Dir(res)
+			if parent == res {
+				return ""
+			}
+			res = parent
+		}
+	}
+	return res
+}
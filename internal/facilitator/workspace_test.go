@@ -0,0 +1,46 @@
+package facilitator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cqfn/refrax/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemory_KeepsClassesInMemory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Foo.java")
+	require.NoError(t, os.WriteFile(path, []byte("original"), 0o600))
+	job := &domain.Job{Classes: []domain.Class{domain.NewInMemoryClass("Foo", path, "original")}}
+	ws := newWorkspace(job, true)
+
+	require.NoError(t, ws.save(job.Classes[0], "changed"))
+
+	content, err := ws.content(job.Classes[0])
+	require.NoError(t, err)
+	assert.Equal(t, "changed", content)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "original", string(data))
+}
+
+func TestMemory_ChecksOutClassesUnderTheirCommonDirectory(t *testing.T) {
+	job := &domain.Job{Classes: []domain.Class{
+		domain.NewInMemoryClass("Foo", "/home/user/app/src/main/Foo.java", "class Foo {}"),
+		domain.NewInMemoryClass("Bar", "/home/user/app/src/test/Bar.java", "class Bar {}"),
+	}}
+	ws := newWorkspace(job, true)
+
+	dir, cleanup, err := ws.checkout()
+	require.NoError(t, err)
+	defer cleanup()
+
+	foo, err := os.ReadFile(filepath.Join(dir, "main", "Foo.java"))
+	require.NoError(t, err)
+	assert.Equal(t, "class Foo {}", string(foo))
+	bar, err := os.ReadFile(filepath.Join(dir, "test", "Bar.java"))
+	require.NoError(t, err)
+	assert.Equal(t, "class Bar {}", string(bar))
+}
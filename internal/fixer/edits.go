@@ -0,0 +1,143 @@
+package fixer
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	searchMark  = "<<<<<<< SEARCH"
+	dividerMark = "======="
+	replaceMark = ">>>>>>> REPLACE"
+	coversMark  = "COVERS:"
+)
+
+// edit replaces a fragment of the original code with the new one.
+type edit struct {
+	search  string
+	replace string
+	// covers are the numbers of the suggestions the edit applies, starting from 1.
+	covers []int
+}
+
+// parseEdits reads the search/replace blocks of the answer. Each block may be preceded by
+// a "COVERS: 1, 2" line with the numbers of the suggestions it applies.
+// The text outside the blocks, e.g. markdown fences, is ignored.
+func parseEdits(answer string) ([]edit, error) {
+	res := make([]edit, 0)
+	var covers []int
+	lines := strings.Split(strings.ReplaceAll(answer, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(line, coversMark) {
+			covers = numbers(strings.TrimPrefix(line, coversMark))
+			continue
+		}
+		if line != searchMark {
+			continue
+		}
+		search := make([]string, 0)
+		for i++; i < len(lines) && strings.TrimSpace(lines[i]) != dividerMark; i++ {
+			search = append(search, lines[i])
+		}
+		replace := make([]string, 0)
+		for i++; i < len(lines) && strings.TrimSpace(lines[i]) != replaceMark; i++ {
+			replace = append(replace, lines[i])
+		}
+		if i >= len(lines) {
+			return nil, fmt.Errorf("edit %d is not closed with %q", len(res)+1, replaceMark)
+		}
+		res = append(res, edit{search: strings.Join(search, "\n"), replace: strings.Join(replace, "\n"), covers: covers})
+		covers = nil
+	}
+	if len(res) == 0 {
+		return nil, fmt.Errorf("the answer has no %q blocks", searchMark)
+	}
+	return res, nil
+}
+
+// applyEdits applies the edits to the code one by one.
+// It fails if any edit doesn't apply, listing all the edits that don't.
+func applyEdits(code string, edits []edit) (string, error) {
+	problems := make([]error, 0)
+	for i, e := range edits {
+		changed, err := e.apply(code)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("edit %d doesn't apply: %w", i+1, err))
+			continue
+		}
+		code = changed
+	}
+	return code, errors.Join(problems...)
+}
+
+// apply replaces the only place where the lines of the code are the lines of the searched fragment.
+// The fragment is matched on whole lines, so that a fragment of a line never changes another line that contains it.
+// If the lines are not found as is, they are matched ignoring the indentation,
+// since models often get the indentation wrong.
+func (e edit) apply(code string) (string, error) {
+	if strings.TrimSpace(e.search) == "" {
+		return "", fmt.Errorf("the SEARCH part is empty")
+	}
+	lines := strings.Split(code, "\n")
+	search := strings.Split(e.search, "\n")
+	found, err := locate(lines, search, func(line, searched string) bool { return line == searched })
+	if err != nil {
+		return "", err
+	}
+	if found < 0 {
+		found, err = locate(lines, search, func(line, searched string) bool {
+			return strings.TrimSpace(line) == strings.TrimSpace(searched)
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	if found < 0 {
+		return "", fmt.Errorf("the SEARCH part is not found in the code:\n%s", e.search)
+	}
+	res := append([]string{}, lines[:found]...)
+	if e.replace != "" {
+		res = append(res, strings.Split(e.replace, "\n")...)
+	}
+	res = append(res, lines[found+len(search):]...)
+	return strings.Join(res, "\n"), nil
+}
+
+// locate returns the first line of the only place where the lines are the same as the searched ones,
+// or -1 if there is no such place. It fails if there are several.
+func locate(lines, search []string, same func(line, searched string) bool) (int, error) {
+	found := -1
+	for i := 0; i+len(search) <= len(lines); i++ {
+		if matches(lines[i:i+len(search)], search, same) {
+			if found >= 0 {
+				return -1, fmt.Errorf("the SEARCH part matches several places, add more lines to make it unique")
+			}
+			found = i
+		}
+	}
+	return found, nil
+}
+
+// matches compares the lines one by one.
+func matches(lines, search []string, same func(line, searched string) bool) bool {
+	for i := range search {
+		if !same(lines[i], search[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// numbers reads the comma-separated numbers, skipping anything that is not a number.
+func numbers(text string) []int {
+	res := make([]int, 0)
+	for _, f := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ' ' || r == '#' }) {
+		if n, err := strconv.Atoi(f); err == nil {
+			res = append(res, n)
+		}
+	}
+	return res
+}
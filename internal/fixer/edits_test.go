@@ -0,0 +1,113 @@
+package fixer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cqfn/refrax/internal/domain"
+	"github.com/cqfn/refrax/internal/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const inlined = "COVERS: 1\n" +
+	"<<<<<<< SEARCH\n" +
+	"    int n = name.length();\n" +
+	"    return n;\n" +
+	"=======\n" +
+	"    return name.length();\n" +
+	">>>>>>> REPLACE\n"
+
+func TestParseEdits_ReadsBlocksWithCoveredSuggestions(t *testing.T) {
+	answer := "```\n" + inlined + "COVERS: 2, 3\n<<<<<<< SEARCH\nString name\n=======\nfinal String name\n>>>>>>> REPLACE\n```"
+
+	edits, err := parseEdits(answer)
+
+	require.NoError(t, err)
+	require.Len(t, edits, 2)
+	assert.Equal(t, "    int n = name.length();\n    return n;", edits[0].search)
+	assert.Equal(t, "    return name.length();", edits[0].replace)
+	assert.Equal(t, []int{1}, edits[0].covers)
+	assert.Equal(t, []int{2, 3}, edits[1].covers)
+}
+
+func TestParseEdits_RejectsUnclosedBlock(t *testing.T) {
+	_, err := parseEdits("<<<<<<< SEARCH\nint n;\n=======\nint m;\n")
+
+	assert.ErrorContains(t, err, "is not closed")
+}
+
+func TestParseEdits_RejectsAnswerWithoutEdits(t *testing.T) {
+	_, err := parseEdits(original)
+
+	assert.ErrorContains(t, err, "has no")
+}
+
+func TestApplyEdits_ReplacesFragment(t *testing.T) {
+	edits, err := parseEdits(inlined)
+	require.NoError(t, err)
+
+	fixed, err := applyEdits(original, edits)
+
+	require.NoError(t, err)
+	assert.Equal(t, "package a;\n\npublic class Foo {\n  public int size(String name) {\n    return name.length();\n  }\n}\n", fixed)
+}
+
+func TestApplyEdits_IgnoresWrongIndentation(t *testing.T) {
+	fixed, err := applyEdits(original, []edit{{search: "int n = name.length();\nreturn n;", replace: "    return name.length();"}})
+
+	require.NoError(t, err)
+	assert.Contains(t, fixed, "  public int size(String name) {\n    return name.length();\n  }")
+}
+
+func TestApplyEdits_RejectsMissingFragment(t *testing.T) {
+	_, err := applyEdits(original, []edit{{search: "return m;", replace: "return 0;"}})
+
+	assert.ErrorContains(t, err, "edit 1 doesn't apply: the SEARCH part is not found")
+}
+
+func TestApplyEdits_RejectsAmbiguousFragment(t *testing.T) {
+	_, err := applyEdits("class Foo {\n  int x;\n  int x;\n}\n", []edit{{search: "  int x;", replace: "  int y;"}})
+
+	assert.ErrorContains(t, err, "matches several places")
+}
+
+func TestApplyEdits_MatchesWholeLinesOnly(t *testing.T) {
+	_, err := applyEdits(original, []edit{{search: "name", replace: "text"}})
+
+	assert.ErrorContains(t, err, "the SEARCH part is not found")
+}
+
+func TestApplyEdits_PrefersExactLinesToIndentedOnes(t *testing.T) {
+	fixed, err := applyEdits(original, []edit{{search: "}", replace: "}\n// end"}})
+
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(fixed, "  }\n}\n// end\n"), "Only the closing brace of the class should be matched")
+}
+
+func TestFixer_AppliesEditsAndReportsCoveredSuggestions(t *testing.T) {
+	ai := &scripted{answers: []string{"COVERS: 1\n<<<<<<< SEARCH\nreturn x;\n=======\n>>>>>>> REPLACE", inlined}}
+	f := &Fixer{brain: ai, log: log.NewMock()}
+	job := &domain.Job{
+		Descr:   &domain.Description{Text: "fix the class", Meta: map[string]any{"fix-mode": "edits"}},
+		Classes: []domain.Class{domain.NewInMemoryClass("Foo", "Foo.java", original)},
+		Suggestions: []domain.Suggestion{
+			*domain.NewSuggestion("Inline variable n", "Foo.java"),
+			*domain.NewSuggestion("Make the parameter final", "Foo.java"),
+		},
+	}
+
+	msg, err := f.thinkLong(context.Background(), job.Marshal().Message)
+
+	require.NoError(t, err)
+	artifacts, err := domain.UnmarshalArtifacts(msg)
+	require.NoError(t, err)
+	assert.Contains(t, artifacts.Classes[0].Content(), "    return name.length();\n")
+	assert.Contains(t, artifacts.Descr.Text, "edit 1 covers: Inline variable n")
+	require.Len(t, artifacts.Suggestions, 1)
+	assert.Equal(t, "Inline variable n", artifacts.Suggestions[0].Text)
+	require.Len(t, ai.questions, 2)
+	assert.Contains(t, ai.questions[0], "2. Foo.java: Make the parameter final")
+	assert.Contains(t, ai.questions[1], "the SEARCH part is not found")
+}
@@ -13,6 +13,9 @@ import (
 	"github.com/cqfn/refrax/internal/protocol"
 )
 
+// Skill is the ID of the skill the fixer agent declares in its agent card.
+const Skill = "fix-java-code"
+
 // Fixer is a server that fixes Java code based on suggestions provided.
 type Fixer struct {
 	server protocol.Server
@@ -26,6 +29,22 @@ type promptData struct {
 	FilePath    string
 	Code        string
 	Suggestions []domain.Suggestion
+	Numbered    []numbered
+	Skeleton    string
+	Context     []domain.Class
+	Examples    []domain.Class
+}
+
+// numbered is a suggestion along with its number, so that edits can refer to it.
+type numbered struct {
+	domain.Suggestion
+	Number int
+}
+
+// retryData holds the data of the prompt that asks the brain to fix a rejected answer.
+type retryData struct {
+	Question string
+	Problems string
 }
 
 // NewFixer creates a new Fixer instance with the provided AI brain and port.
@@ -46,11 +65,11 @@ func NewFixer(ai brain.Brain, port int, colorless bool) *Fixer {
 
 // Fix applies the given suggestions to the provided class and returns the modified class or an error.
 // It communicates with an external fixer service to perform the modifications.
-func (f *Fixer) Fix(job *domain.Job) (*domain.Artifacts, error) {
+func (f *Fixer) Fix(ctx context.Context, job *domain.Job) (*domain.Artifacts, error) {
 	address := fmt.Sprintf("http://localhost:%d", f.port)
 	f.log.Debug("Asking fixer (%s) to apply suggestions...", address)
 	fixer := protocol.NewClient(address)
-	resp, err := fixer.SendMessage(job.Marshal())
+	resp, err := fixer.SendMessage(ctx, job.Marshal())
 	if err != nil {
 		return nil, fmt.Errorf("failed to send message to fixer: %w", err)
 	}
@@ -91,7 +110,7 @@ func (f *Fixer) think(ctx context.Context, m *protocol.Message) (*protocol.Messa
 	select {
 	case <-ctx.Done():
 		return nil, fmt.Errorf("context canceled: %w", ctx.Err())
-	case res := <-f.thinkChan(m):
+	case res := <-f.thinkChan(ctx, m):
 		return res.msg, res.err
 	}
 }
@@ -101,10 +120,10 @@ type thought struct {
 	err error
 }
 
-func (f *Fixer) thinkChan(m *protocol.Message) <-chan thought {
+func (f *Fixer) thinkChan(ctx context.Context, m *protocol.Message) <-chan thought {
 	res := make(chan thought, 1)
 	go func() {
-		msg, err := f.thinkLong(m)
+		msg, err := f.thinkLong(ctx, m)
 		res <- thought{
 			msg, err,
 		}
@@ -113,7 +132,7 @@ func (f *Fixer) thinkChan(m *protocol.Message) <-chan thought {
 	return res
 }
 
-func (f *Fixer) thinkLong(m *protocol.Message) (*protocol.Message, error) {
+func (f *Fixer) thinkLong(ctx context.Context, m *protocol.Message) (*protocol.Message, error) {
 	f.log.Info("Received message: #%s", m.MessageID)
 	job, err := domain.UnmarshalJob(m)
 	if err != nil {
@@ -125,34 +144,122 @@ func (f *Fixer) thinkLong(m *protocol.Message) (*protocol.Message, error) {
 	code = job.Classes[0].Content()
 	class = job.Classes[0].Name()
 	path = job.Classes[0].Path()
-	f.log.Info("Trying to fix the %q class...", class)
-	prompt := prompts.User{
-		Data: promptData{
-			FilePath:    path,
-			Code:        code,
-			Suggestions: job.Suggestions,
-		},
-		Name: "fixer/fix.md.tmpl",
+	mode := job.FixMode()
+	f.log.Info("Trying to fix the %q class in %s mode...", class, mode)
+	data := promptData{
+		FilePath:    path,
+		Code:        code,
+		Suggestions: job.Suggestions,
+		Numbered:    make([]numbered, 0, len(job.Suggestions)),
+		Context:     job.Context,
+		Examples:    job.Examples,
+	}
+	if s, ok := job.Param("skeleton"); ok {
+		data.Skeleton = fmt.Sprintf("%v", s)
+	}
+	for i, s := range job.Suggestions {
+		data.Numbered = append(data.Numbered, numbered{Suggestion: s, Number: i + 1})
+	}
+	prompt := prompts.User{Data: data, Name: "fixer/fix.md.tmpl"}
+	if mode == "edits" {
+		prompt.Name = "fixer/edits.md.tmpl"
 	}
 	question := prompt.String()
-	f.log.Debug("Asking the brain to fix the Java code...")
-	answer, err := f.brain.Ask(question)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get answer from AI: %w", err)
+	fixed := code
+	descr := fmt.Sprintf("Fix for class %s", class)
+	var covered []domain.Suggestion
+	for attempt := 1; attempt <= attempts; attempt++ {
+		f.log.Debug("Asking the brain to fix the Java code...")
+		answer, aerr := f.brain.Ask(ctx, question)
+		if aerr != nil {
+			return nil, fmt.Errorf("failed to get answer from AI: %w", aerr)
+		}
+		f.log.Debug("Received answer from AI: %s", answer)
+		candidate := clean(answer)
+		var edits []edit
+		var problems error
+		if mode == "edits" {
+			candidate, edits, problems = edited(code, answer)
+		}
+		if problems == nil {
+			problems = validate(path, code, candidate)
+		}
+		if problems == nil {
+			f.log.Info("AI provided a fix for the Java code, sending response back...")
+			fixed = candidate
+			if mode == "edits" {
+				descr, covered = report(class, edits, job.Suggestions)
+				f.log.Info("%s", descr)
+			}
+			break
+		}
+		f.log.Warn("Fix for class %q is rejected (attempt %d/%d): %v", class, attempt, attempts, problems)
+		if attempt == attempts {
+			descr = fmt.Sprintf("Fix for class %s is rejected, the class is left as is: %v", class, problems)
+			break
+		}
+		retry := prompts.User{
+			Data: retryData{
+				Question: prompt.String(),
+				Problems: problems.Error(),
+			},
+			Name: "fixer/retry.md.tmpl",
+		}
+		question = retry.String()
 	}
-	f.log.Debug("Received answer from AI: %s", answer)
-	f.log.Info("AI provided a fix for the Java code, sending response back...")
 	res := &domain.Artifacts{
 		Descr: &domain.Description{
-			Text: fmt.Sprintf("Fix for class %s", class),
+			Text: descr,
 		},
 		Classes: []domain.Class{
-			domain.NewInMemoryClass(class, path, clean(answer)),
+			domain.NewInMemoryClass(class, path, fixed),
 		},
+		Suggestions: covered,
 	}
 	return res.Marshal().Message, nil
 }
 
+// edited applies the edits of the answer to the code.
+func edited(code, answer string) (string, []edit, error) {
+	edits, err := parseEdits(answer)
+	if err != nil {
+		return "", nil, err
+	}
+	res, err := applyEdits(code, edits)
+	if err != nil {
+		return "", nil, err
+	}
+	return res, edits, nil
+}
+
+// report describes which suggestions each edit covered and returns the covered suggestions in their order.
+func report(class string, edits []edit, suggestions []domain.Suggestion) (string, []domain.Suggestion) {
+	var descr strings.Builder
+	fmt.Fprintf(&descr, "Fix for class %s with %d edits", class, len(edits))
+	seen := make(map[int]bool)
+	for i, e := range edits {
+		texts := make([]string, 0, len(e.covers))
+		for _, n := range e.covers {
+			if n < 1 || n > len(suggestions) {
+				continue
+			}
+			texts = append(texts, suggestions[n-1].Text)
+			seen[n] = true
+		}
+		if len(texts) == 0 {
+			texts = append(texts, "no suggestions reported")
+		}
+		fmt.Fprintf(&descr, "\nedit %d covers: %s", i+1, strings.Join(texts, "; "))
+	}
+	covered := make([]domain.Suggestion, 0, len(seen))
+	for i, s := range suggestions {
+		if seen[i+1] {
+			covered = append(covered, s)
+		}
+	}
+	return descr.String(), covered
+}
+
 func clean(answer string) string {
 	answer = strings.ReplaceAll(answer, "```java", "")
 	return strings.ReplaceAll(answer, "```", "")
@@ -164,5 +271,5 @@ func agentCard(port int) *protocol.AgentCard {
 		WithDescription("Fixer Description").
 		WithURL(fmt.Sprintf("http://localhost:%d", port)).
 		WithVersion("0.0.1").
-		AddSkill("fix-java-code", "Fix Java Code", "Fix a Java code based on suggestions")
+		AddSkill(Skill, "Fix Java Code", "Fix a Java code based on suggestions")
 }
@@ -0,0 +1,47 @@
+package fixer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cqfn/refrax/internal/domain"
+	"github.com/cqfn/refrax/internal/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixer_ShowsRelatedClassesInPrompt(t *testing.T) {
+	ai := &scripted{answers: []string{original}}
+	f := &Fixer{brain: ai, log: log.NewMock()}
+	job := &domain.Job{
+		Descr:       &domain.Description{Text: "fix the class"},
+		Classes:     []domain.Class{domain.NewInMemoryClass("Foo", "Foo.java", original)},
+		Suggestions: []domain.Suggestion{*domain.NewSuggestion("Remove unused parameter", "Foo.java")},
+		Context: []domain.Class{
+			domain.NewInMemoryClass("Caller", "b/Caller.java", "class Caller\nCalls of Foo:\n  line 7: return foo.size(\"x\");"),
+		},
+	}
+
+	_, err := f.thinkLong(context.Background(), job.Marshal().Message)
+
+	require.NoError(t, err)
+	require.Len(t, ai.questions, 1)
+	assert.Contains(t, ai.questions[0], "## Related Classes")
+	assert.Contains(t, ai.questions[0], "b/Caller.java:\n```\nclass Caller\nCalls of Foo:\n  line 7: return foo.size(\"x\");\n```")
+}
+
+func TestFixer_ShowsStyleExamplesInPrompt(t *testing.T) {
+	ai := &scripted{answers: []string{original}}
+	f := &Fixer{brain: ai, log: log.NewMock()}
+	job := &domain.Job{
+		Descr:    &domain.Description{Text: "fix the class"},
+		Classes:  []domain.Class{domain.NewInMemoryClass("Foo", "Foo.java", original)},
+		Examples: []domain.Class{domain.NewInMemoryClass("Order", "a/Order.java", "final class Order {}")},
+	}
+
+	_, err := f.thinkLong(context.Background(), job.Marshal().Message)
+
+	require.NoError(t, err)
+	assert.Contains(t, ai.questions[0], "## Style Examples")
+	assert.Contains(t, ai.questions[0], "a/Order.java:\n```\nfinal class Order {}\n```")
+}
@@ -0,0 +1,65 @@
+package fixer
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/cqfn/refrax/internal/java"
+)
+
+// attempts is how many times the fixer asks the brain for code that passes the validation.
+const attempts = 3
+
+// validate checks that the fixed code is Java that keeps the package, the module, the top-level types
+// and the public method signatures of the original code. If the original code can't be parsed,
+// only the syntax of the fixed code is checked. A package-info.java is not checked, since it has no types
+// and can't be told from a truncated class.
+func validate(path, original, fixed string) error {
+	if filepath.Base(path) == "package-info.java" {
+		return nil
+	}
+	after, err := java.Parse(fixed)
+	if err != nil {
+		return fmt.Errorf("the code is not valid Java: %w", err)
+	}
+	before, err := java.Parse(original)
+	if err != nil {
+		return nil
+	}
+	problems := make([]string, 0)
+	if before.Package != after.Package {
+		problems = append(problems, fmt.Sprintf("the package is changed from %q to %q", before.Package, after.Package))
+	}
+	if before.Module != after.Module {
+		problems = append(problems, fmt.Sprintf("the module is changed from %q to %q", before.Module, after.Module))
+	}
+	for _, b := range before.Types {
+		i := slices.IndexFunc(after.Types, func(t java.Type) bool { return t.Name == b.Name })
+		if i < 0 {
+			problems = append(problems, fmt.Sprintf("%s %s is missing or renamed", b.Kind, b.Name))
+			continue
+		}
+		for _, m := range b.Methods {
+			if !slices.Contains(after.Types[i].Methods, m) {
+				problems = append(problems, fmt.Sprintf("public method %s of %s is missing or its signature is changed", m, b.Name))
+			}
+		}
+		for _, m := range after.Types[i].Methods {
+			if !slices.Contains(b.Methods, m) {
+				problems = append(problems, fmt.Sprintf("public method %s is added to %s", m, b.Name))
+			}
+		}
+	}
+	for _, a := range after.Types {
+		if !slices.ContainsFunc(before.Types, func(t java.Type) bool { return t.Name == a.Name }) {
+			problems = append(problems, fmt.Sprintf("%s %s is added", a.Kind, a.Name))
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
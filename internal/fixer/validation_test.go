@@ -0,0 +1,118 @@
+package fixer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cqfn/refrax/internal/domain"
+	"github.com/cqfn/refrax/internal/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const original = "package a;\n\npublic class Foo {\n  public int size(String name) {\n    int n = name.length();\n    return n;\n  }\n}\n"
+
+// scripted is a brain that gives the answers one by one and remembers the questions.
+type scripted struct {
+	answers   []string
+	questions []string
+}
+
+func (s *scripted) Ask(_ context.Context, question string) (string, error) {
+	s.questions = append(s.questions, question)
+	answer := s.answers[0]
+	s.answers = s.answers[1:]
+	return answer, nil
+}
+
+func TestValidate_AcceptsRefactoredClass(t *testing.T) {
+	fixed := "package a;\n\npublic class Foo {\n  public int size(final String name) {\n    return name.length();\n  }\n}\n"
+
+	assert.NoError(t, validate("a/Foo.java", original, fixed))
+}
+
+func TestValidate_RejectsTruncatedClass(t *testing.T) {
+	err := validate("a/Foo.java", original, "package a;\n\npublic class Foo {\n  public int size(String name) {\n")
+
+	assert.ErrorContains(t, err, "not valid Java")
+}
+
+func TestValidate_RejectsRenamedClass(t *testing.T) {
+	err := validate("a/Foo.java", original, "package a;\n\npublic class Bar {\n  public int size(String name) {\n    return 0;\n  }\n}\n")
+
+	assert.ErrorContains(t, err, "class Foo is missing or renamed")
+}
+
+func TestValidate_RejectsChangedSignature(t *testing.T) {
+	err := validate("a/Foo.java", original, "package a;\n\npublic class Foo {\n  public long size(CharSequence name) {\n    return 0;\n  }\n}\n")
+
+	assert.ErrorContains(t, err, "public method size(String) of Foo is missing")
+}
+
+func TestValidate_RejectsChangedPackage(t *testing.T) {
+	err := validate("a/Foo.java", original, "package b;\n\npublic class Foo {\n  public int size(String name) {\n    return 0;\n  }\n}\n")
+
+	assert.ErrorContains(t, err, "package is changed")
+}
+
+func TestFixer_AsksAgainWithProblemsOfRejectedAnswer(t *testing.T) {
+	fixed := "package a;\n\npublic class Foo {\n  public int size(String name) {\n    return name.length();\n  }\n}\n"
+	ai := &scripted{answers: []string{"I inlined the variable:\n" + fixed, "```java\n" + fixed + "```"}}
+	f := &Fixer{brain: ai, log: log.NewMock()}
+	job := &domain.Job{
+		Descr:       &domain.Description{Text: "fix the class"},
+		Classes:     []domain.Class{domain.NewInMemoryClass("Foo", "Foo.java", original)},
+		Suggestions: []domain.Suggestion{*domain.NewSuggestion("Inline variable n", "Foo.java")},
+	}
+
+	msg, err := f.thinkLong(context.Background(), job.Marshal().Message)
+
+	require.NoError(t, err)
+	artifacts, err := domain.UnmarshalArtifacts(msg)
+	require.NoError(t, err)
+	assert.Equal(t, "\n"+fixed, artifacts.Classes[0].Content())
+	require.Len(t, ai.questions, 2)
+	assert.Contains(t, ai.questions[1], "Previous Answer Was Rejected")
+	assert.Contains(t, ai.questions[1], "unexpected \"I\"")
+}
+
+func TestFixer_KeepsClassWhenAllAnswersAreRejected(t *testing.T) {
+	ai := &scripted{answers: []string{"Sorry", "Sorry", "Sorry"}}
+	f := &Fixer{brain: ai, log: log.NewMock()}
+	job := &domain.Job{
+		Descr:   &domain.Description{Text: "fix the class"},
+		Classes: []domain.Class{domain.NewInMemoryClass("Foo", "Foo.java", original)},
+	}
+
+	msg, err := f.thinkLong(context.Background(), job.Marshal().Message)
+
+	require.NoError(t, err)
+	artifacts, err := domain.UnmarshalArtifacts(msg)
+	require.NoError(t, err)
+	assert.Equal(t, original, artifacts.Classes[0].Content())
+	assert.Contains(t, artifacts.Descr.Text, "rejected")
+	assert.Len(t, ai.questions, attempts)
+}
+
+func TestValidate_AcceptsPackageInfo(t *testing.T) {
+	before := "/**\n * Services.\n */\n@ParametersAreNonnullByDefault\npackage a;\n\nimport javax.annotation.ParametersAreNonnullByDefault;\n"
+	after := "/**\n * The services of the application.\n */\n@ParametersAreNonnullByDefault\npackage a;\n\nimport javax.annotation.ParametersAreNonnullByDefault;\n"
+
+	assert.NoError(t, validate("src/main/java/a/package-info.java", before, after))
+}
+
+func TestValidate_AcceptsModuleInfo(t *testing.T) {
+	before := "module com.example.app {\n  requires java.sql;\n  exports com.example.app.api;\n}\n"
+	after := "module com.example.app {\n  requires transitive java.sql;\n  exports com.example.app.api;\n}\n"
+
+	assert.NoError(t, validate("src/main/java/module-info.java", before, after))
+}
+
+func TestValidate_RejectsRenamedModule(t *testing.T) {
+	before := "module com.example.app {\n  requires java.sql;\n}\n"
+
+	err := validate("src/main/java/module-info.java", before, "module com.example.core {\n  requires java.sql;\n}\n")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "the module is changed")
+}
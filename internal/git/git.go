@@ -0,0 +1,136 @@
+// Package git finds the files that changed in a local git repository.
+// It only runs local git commands and never reaches the network.
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Repo is a local git repository.
+type Repo struct {
+	root string
+}
+
+// Open finds the git repository that the directory belongs to.
+func Open(dir string) (*Repo, error) {
+	out, err := run(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("%s is not inside a git repository: %w", dir, err)
+	}
+	return &Repo{root: strings.TrimSpace(string(out))}, nil
+}
+
+// Since returns the files that changed since the common ancestor of the ref and HEAD,
+// e.g. the files a pull request touches when the ref is "origin/main".
+// Uncommitted and untracked files count as changed too. The paths are absolute.
+func (r *Repo) Since(ref string) ([]string, error) {
+	out, err := run(r.root, "merge-base", ref, "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the common ancestor of %s and HEAD: %w", ref, err)
+	}
+	base := strings.TrimSpace(string(out))
+	changed, err := r.files("diff", "--name-only", "-z", "-M", "--diff-filter=ACMR", base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find files changed since %s: %w", ref, err)
+	}
+	untracked, err := r.files("ls-files", "-z", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find untracked files: %w", err)
+	}
+	for _, f := range untracked {
+		if !slices.Contains(changed, f) {
+			changed = append(changed, f)
+		}
+	}
+	return changed, nil
+}
+
+// Staged returns the files that are added, modified or renamed in the index. The paths are absolute.
+func (r *Repo) Staged() ([]string, error) {
+	staged, err := r.files("diff", "--cached", "--name-only", "-z", "-M", "--diff-filter=ACMR")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find staged files: %w", err)
+	}
+	return staged, nil
+}
+
+// Root returns the absolute path of the repository root.
+func (r *Repo) Root() string {
+	return r.root
+}
+
+// Branch creates a new branch at HEAD and switches to it, the working tree stays as it is.
+func (r *Repo) Branch(name string) error {
+	if _, err := run(r.root, "switch", "-c", name); err != nil {
+		return fmt.Errorf("failed to create branch %s: %w", name, err)
+	}
+	return nil
+}
+
+// Commit stages the files and commits them with the message.
+// It returns false without committing if the files have no changes.
+func (r *Repo) Commit(message string, files ...string) (bool, error) {
+	files, err := absolute(files)
+	if err != nil {
+		return false, err
+	}
+	if _, err := run(r.root, append([]string{"add", "--"}, files...)...); err != nil {
+		return false, fmt.Errorf("failed to stage files: %w", err)
+	}
+	if _, err := run(r.root, append([]string{"diff", "--cached", "--quiet", "--"}, files...)...); err == nil {
+		return false, nil
+	}
+	if _, err := run(r.root, append([]string{"commit", "-q", "-m", message, "--"}, files...)...); err != nil {
+		return false, fmt.Errorf("failed to commit: %w", err)
+	}
+	return true, nil
+}
+
+// files runs a git command that prints NUL-separated paths relative to the repository root.
+func (r *Repo) files(args ...string) ([]string, error) {
+	out, err := run(r.root, args...)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]string, 0)
+	for _, name := range strings.Split(string(out), "\x00") {
+		if name != "" {
+			res = append(res, filepath.Join(r.root, filepath.FromSlash(name)))
+		}
+	}
+	return res, nil
+}
+
+// absolute makes the paths absolute, since git runs in the repository root rather than in the current directory.
+func absolute(files []string) ([]string, error) {
+	res := make([]string, 0, len(files))
+	for _, f := range files {
+		abs, err := filepath.Abs(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve path %s: %w", f, err)
+		}
+		res = append(res, abs)
+	}
+	return res, nil
+}
+
+// run executes git in the directory. Prompts and lazy fetches of partial clones are disabled,
+// so the command either works with what is on the disk or fails.
+func run(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_NO_LAZY_FETCH=1")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
@@ -0,0 +1,159 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen_FailsOutsideRepository(t *testing.T) {
+	skipWithoutGit(t)
+
+	_, err := Open(t.TempDir())
+
+	require.Error(t, err)
+}
+
+func TestRepo_Since_FindsChangedAddedRenamedAndUntrackedFiles(t *testing.T) {
+	dir := repository(t)
+	write(t, dir, "Kept.java", "class Kept {}")
+	write(t, dir, "Modified.java", "class Modified {}")
+	write(t, dir, "Old.java", "class Old { int a; int b; int c; }")
+	write(t, dir, "Deleted.java", "class Deleted {}")
+	git(t, dir, "add", "-A")
+	git(t, dir, "commit", "-m", "base")
+	git(t, dir, "branch", "base")
+	write(t, dir, "Modified.java", "class Modified { int x; }")
+	git(t, dir, "mv", "Old.java", "New.java")
+	require.NoError(t, os.Remove(filepath.Join(dir, "Deleted.java")))
+	write(t, dir, "Added.java", "class Added {}")
+	git(t, dir, "add", "-A")
+	git(t, dir, "commit", "-m", "change")
+	write(t, dir, "Untracked.java", "class Untracked {}")
+	repo, err := Open(dir)
+	require.NoError(t, err)
+
+	files, err := repo.Since("base")
+
+	require.NoError(t, err)
+	assert.ElementsMatch(t, abs(dir, "Modified.java", "New.java", "Added.java", "Untracked.java"), files)
+}
+
+func TestRepo_Since_FailsOnUnknownRef(t *testing.T) {
+	dir := repository(t)
+	write(t, dir, "Foo.java", "class Foo {}")
+	git(t, dir, "add", "-A")
+	git(t, dir, "commit", "-m", "base")
+	repo, err := Open(dir)
+	require.NoError(t, err)
+
+	_, err = repo.Since("origin/unknown")
+
+	require.Error(t, err)
+}
+
+func TestRepo_Staged_FindsStagedFilesOnly(t *testing.T) {
+	dir := repository(t)
+	write(t, dir, "Foo.java", "class Foo {}")
+	git(t, dir, "add", "-A")
+	git(t, dir, "commit", "-m", "base")
+	write(t, dir, "Foo.java", "class Foo { int x; }")
+	write(t, dir, "Bar.java", "class Bar {}")
+	git(t, dir, "add", "Bar.java")
+	repo, err := Open(dir)
+	require.NoError(t, err)
+
+	files, err := repo.Staged()
+
+	require.NoError(t, err)
+	assert.Equal(t, abs(dir, "Bar.java"), files)
+}
+
+func repository(t *testing.T) string {
+	t.Helper()
+	skipWithoutGit(t)
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	git(t, dir, "init", "-q")
+	git(t, dir, "config", "user.email", "test@example.com")
+	git(t, dir, "config", "user.name", "test")
+	git(t, dir, "config", "commit.gpgsign", "false")
+	return dir
+}
+
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func write(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+}
+
+func abs(dir string, names ...string) []string {
+	res := make([]string, 0, len(names))
+	for _, n := range names {
+		res = append(res, filepath.Join(dir, n))
+	}
+	return res
+}
+
+func skipWithoutGit(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+}
+
+func TestRepo_Commit_CommitsGivenFilesOnBranch(t *testing.T) {
+	dir := repository(t)
+	write(t, dir, "Foo.java", "class Foo {}")
+	write(t, dir, "Bar.java", "class Bar {}")
+	git(t, dir, "add", "-A")
+	git(t, dir, "commit", "-m", "base")
+	repo, err := Open(dir)
+	require.NoError(t, err)
+	require.NoError(t, repo.Branch("refrax"))
+	write(t, dir, "Foo.java", "final class Foo {}")
+	write(t, dir, "Bar.java", "final class Bar {}")
+
+	done, err := repo.Commit("Make Foo final", filepath.Join(dir, "Foo.java"))
+
+	require.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, "refrax", output(t, dir, "branch", "--show-current"))
+	assert.Equal(t, "Make Foo final", output(t, dir, "log", "-1", "--format=%s"))
+	assert.Equal(t, "Foo.java", output(t, dir, "show", "--name-only", "--format=", "HEAD"))
+}
+
+func TestRepo_Commit_SkipsUnchangedFiles(t *testing.T) {
+	dir := repository(t)
+	write(t, dir, "Foo.java", "class Foo {}")
+	git(t, dir, "add", "-A")
+	git(t, dir, "commit", "-m", "base")
+	repo, err := Open(dir)
+	require.NoError(t, err)
+
+	done, err := repo.Commit("Nothing", filepath.Join(dir, "Foo.java"))
+
+	require.NoError(t, err)
+	assert.False(t, done)
+}
+
+func output(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	require.NoError(t, err)
+	return strings.TrimSpace(string(out))
+}
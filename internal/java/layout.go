@@ -0,0 +1,237 @@
+package java
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Layout is a Java file split into the members of its top-level types.
+// The members of a type cover all the lines between the braces of its body,
+// so a type body can be rebuilt from any of its members and the lines around them.
+type Layout struct {
+	Lines   []string
+	Bodies  []Body
+	Members []Member
+}
+
+// Body is the body of a top-level type, Open and Close are the lines of its braces counting from 1.
+type Body struct {
+	Owner string
+	Open  int
+	Close int
+}
+
+// Member is a field, a method, a constructor, an initializer, the constants of an enum or an inner type.
+// Start and End are its first and last lines counting from 1, the comments above the member are part of it.
+// Members declared on the same line are merged into one.
+type Member struct {
+	Owner string
+	// Signature is the declaration without the body and the initializer, e.g. "public int size(String name)".
+	Signature string
+	Start     int
+	End       int
+}
+
+// Split finds the members of the top-level types of the Java source.
+// It fails if the source can't be read or if a member shares a line with a brace of its type body,
+// e.g. "class Foo { int x; }", since such a member can't be replaced without touching the type.
+func Split(src string) (*Layout, error) {
+	toks, err := Lex(src)
+	if err != nil {
+		return nil, err
+	}
+	if err = balanced(toks); err != nil {
+		return nil, err
+	}
+	res := &Layout{Lines: strings.Split(src, "\n"), Bodies: make([]Body, 0), Members: make([]Member, 0)}
+	p := &parser{toks: toks}
+	for _, b := range p.bodies() {
+		body := Body{Owner: b.owner, Open: toks[b.open].Line, Close: toks[b.close].Line}
+		members, serr := divide(toks, b, body)
+		if serr != nil {
+			return nil, serr
+		}
+		res.Bodies = append(res.Bodies, body)
+		res.Members = append(res.Members, members...)
+	}
+	return res, nil
+}
+
+// Bodies finds the bodies of the top-level types of the Java source.
+// Unlike Split, it doesn't care where the members of the types are.
+func Bodies(src string) ([]Body, error) {
+	toks, err := Lex(src)
+	if err != nil {
+		return nil, err
+	}
+	if err = balanced(toks); err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	res := make([]Body, 0)
+	for _, b := range p.bodies() {
+		res = append(res, Body{Owner: b.owner, Open: toks[b.open].Line, Close: toks[b.close].Line})
+	}
+	return res, nil
+}
+
+// Text returns the lines of the member.
+func (l *Layout) Text(m Member) string {
+	return strings.Join(l.Lines[m.Start-1:m.End], "\n")
+}
+
+// body is the body of a top-level type with the positions of its braces among the tokens.
+type body struct {
+	owner string
+	kind  string
+	open  int
+	close int
+}
+
+// bodies finds the bodies of the top-level types.
+func (p *parser) bodies() []body {
+	res := make([]body, 0)
+	for p.pos < len(p.toks) {
+		t := p.toks[p.pos]
+		switch {
+		case t.Text == "{" || t.Text == "(" || t.Text == "[":
+			p.pos = p.closing(p.pos) + 1
+		case t.Text == "@" && p.peek(1) != "interface":
+			p.annotation()
+		case slices.Contains(kinds, t.Text) || (t.Text == "@" && p.peek(1) == "interface"):
+			kind := t.Text
+			if kind == "@" {
+				kind = "@interface"
+				p.pos++
+			}
+			p.pos++
+			owner := p.peek(0)
+			for p.pos < len(p.toks) && p.toks[p.pos].Text != "{" {
+				p.pos++
+			}
+			if p.pos >= len(p.toks) {
+				return res
+			}
+			end := p.closing(p.pos)
+			res = append(res, body{owner: owner, kind: kind, open: p.pos, close: end})
+			p.pos = end + 1
+		default:
+			p.pos++
+		}
+	}
+	return res
+}
+
+// divide finds the members of the type body.
+func divide(toks []Token, b body, outer Body) ([]Member, error) {
+	p := &parser{toks: toks[:b.close], pos: b.open + 1}
+	res := make([]Member, 0)
+	boundary := outer.Open
+	add := func(first Token, head []Token, last Token) error {
+		if first.Line <= outer.Open || last.Line >= outer.Close {
+			return &Error{
+				Line: first.Line, Col: first.Col,
+				Msg: fmt.Sprintf("a member of %s shares a line with a brace of its body", b.owner),
+			}
+		}
+		if first.Line <= boundary && len(res) > 0 {
+			res[len(res)-1].End = last.Line
+			boundary = last.Line
+			return nil
+		}
+		res = append(res, Member{Owner: b.owner, Signature: signature(head), Start: boundary + 1, End: last.Line})
+		boundary = last.Line
+		return nil
+	}
+	if b.kind == "enum" && p.pos < len(p.toks) {
+		first := p.toks[p.pos]
+		for p.pos < len(p.toks) && p.toks[p.pos].Text != ";" {
+			p.skip()
+		}
+		last := p.toks[min(p.pos, len(p.toks)-1)]
+		if err := add(first, []Token{{Kind: Ident, Text: "constants"}}, last); err != nil {
+			return nil, err
+		}
+		p.pos++
+	}
+	for p.pos < len(p.toks) {
+		first := p.toks[p.pos]
+		head := make([]Token, 0)
+		assigned := false
+		for p.pos < len(p.toks) {
+			t := p.toks[p.pos]
+			if t.Text == ";" || (t.Text == "{" && !assigned) {
+				break
+			}
+			if t.Text == "=" {
+				assigned = true
+			}
+			if t.Text == "(" || t.Text == "[" || t.Text == "{" {
+				end := p.closing(p.pos)
+				head = append(head, p.toks[p.pos:end+1]...)
+				p.pos = end + 1
+				continue
+			}
+			head = append(head, t)
+			p.pos++
+		}
+		if p.pos >= len(p.toks) {
+			if len(head) > 0 {
+				return nil, &Error{Line: first.Line, Col: first.Col, Msg: fmt.Sprintf("a member of %s is not finished", b.owner)}
+			}
+			break
+		}
+		end := p.pos
+		if p.toks[p.pos].Text == "{" {
+			end = p.closing(p.pos)
+		}
+		p.pos = end + 1
+		if len(head) == 0 && p.toks[end].Text == ";" {
+			continue
+		}
+		if err := add(first, head, p.toks[end]); err != nil {
+			return nil, err
+		}
+	}
+	if len(res) > 0 {
+		res[len(res)-1].End = outer.Close - 1
+	}
+	return res, nil
+}
+
+// signature returns the declaration of a member from its head, without annotations and the initializer.
+func signature(head []Token) string {
+	decl := stripped(head)
+	if eq := slices.IndexFunc(decl, func(t Token) bool { return t.Text == "=" }); eq >= 0 {
+		decl = decl[:eq]
+	}
+	res := declared(decl)
+	if res == "" || res == "static" {
+		return strings.TrimSpace(res + " initializer")
+	}
+	return res
+}
+
+// declared joins the tokens the way a declaration is usually written, e.g. "Map<String, Integer> counts(int[] ids)".
+func declared(toks []Token) string {
+	var res strings.Builder
+	for i, t := range toks {
+		if i > 0 && spaced(toks[i-1], t) {
+			res.WriteString(" ")
+		}
+		res.WriteString(t.Text)
+	}
+	return res.String()
+}
+
+// spaced tells whether a declaration has a space between the tokens.
+func spaced(prev, t Token) bool {
+	if slices.Contains([]string{"(", ")", "[", "]", ",", ".", "...", ">", ";"}, t.Text) {
+		return false
+	}
+	if slices.Contains([]string{"(", "[", ".", "<", "@"}, prev.Text) {
+		return false
+	}
+	return t.Text != "<" || prev.Kind != Ident || slices.Contains(modifiers, prev.Text)
+}
@@ -0,0 +1,63 @@
+package java
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplit_FindsMembersWithTheirComments(t *testing.T) {
+	src := "package a;\n" +
+		"\n" +
+		"public class Foo {\n" +
+		"  private final int x = 1; private int y;\n" +
+		"\n" +
+		"  /**\n" +
+		"   * Size of the name.\n" +
+		"   */\n" +
+		"  @Override\n" +
+		"  public int size(String name) {\n" +
+		"    return name.length();\n" +
+		"  }\n" +
+		"\n" +
+		"  static class Inner {\n" +
+		"    int z;\n" +
+		"  }\n" +
+		"  // the end\n" +
+		"}\n"
+
+	layout, err := Split(src)
+
+	require.NoError(t, err)
+	require.Len(t, layout.Members, 3)
+	assert.Equal(t, Member{Owner: "Foo", Signature: "private final int x", Start: 4, End: 4}, layout.Members[0])
+	assert.Equal(t, Member{Owner: "Foo", Signature: "public int size(String name)", Start: 5, End: 12}, layout.Members[1])
+	assert.Equal(t, Member{Owner: "Foo", Signature: "static class Inner", Start: 13, End: 17}, layout.Members[2])
+	assert.Equal(t, []Body{{Owner: "Foo", Open: 3, Close: 18}}, layout.Bodies)
+	assert.Contains(t, layout.Text(layout.Members[1]), "Size of the name")
+}
+
+func TestSplit_ReadsEnumConstantsAsOneMember(t *testing.T) {
+	src := "enum Color {\n  RED,\n  GREEN;\n  int code() {\n    return 0;\n  }\n}\n"
+
+	layout, err := Split(src)
+
+	require.NoError(t, err)
+	require.Len(t, layout.Members, 2)
+	assert.Equal(t, "constants", layout.Members[0].Signature)
+	assert.Equal(t, 3, layout.Members[0].End)
+}
+
+func TestSplit_FailsOnMemberOnBraceLine(t *testing.T) {
+	_, err := Split("class Foo { int x;\n}\n")
+
+	assert.ErrorContains(t, err, "shares a line with a brace")
+}
+
+func TestBodies_FindsTopLevelTypes(t *testing.T) {
+	bodies, err := Bodies("class Foo { int x; }\n\ninterface Bar {\n  void run();\n}\n")
+
+	require.NoError(t, err)
+	assert.Equal(t, []Body{{Owner: "Foo", Open: 1, Close: 1}, {Owner: "Bar", Open: 3, Close: 5}}, bodies)
+}
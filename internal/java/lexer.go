@@ -0,0 +1,186 @@
+// Package java reads Java source files without a JVM: a lexer splits the code into tokens and
+// an outline parser finds the package, the top-level types and their methods. The parser
+// checks the structure of a file, it doesn't check the statements inside method bodies.
+package java
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kind is the kind of a token.
+type Kind int
+
+const (
+	// Ident is an identifier or a keyword.
+	Ident Kind = iota
+	// Literal is a number, a character, a string or a text block.
+	Literal
+	// Punct is an operator or a separator.
+	Punct
+)
+
+// Token is a lexical unit of Java code. Comments and whitespace are not tokens.
+type Token struct {
+	Kind Kind
+	Text string
+	Line int
+	Col  int
+}
+
+// Error is a problem found in the Java code at the given position.
+type Error struct {
+	Line int
+	Col  int
+	Msg  string
+}
+
+// lexer splits the source into tokens.
+type lexer struct {
+	src  string
+	pos  int
+	line int
+	col  int
+}
+
+// Lex splits the Java source into tokens. It fails on unterminated comments and literals
+// and on characters that can't appear in Java code outside of them, e.g. markdown backticks.
+func Lex(src string) ([]Token, error) {
+	l := &lexer{src: src, line: 1, col: 1}
+	res := make([]Token, 0, len(src)/4)
+	for {
+		l.skip()
+		if l.pos >= len(l.src) {
+			return res, nil
+		}
+		tok, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, tok)
+	}
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Col, e.Msg)
+}
+
+// skip moves past whitespace and comments. An unterminated block comment is left for next to report.
+func (l *lexer) skip() {
+	for l.pos < len(l.src) {
+		switch {
+		case strings.HasPrefix(l.src[l.pos:], "//"):
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.advance()
+			}
+		case strings.HasPrefix(l.src[l.pos:], "/*"):
+			end := strings.Index(l.src[l.pos+2:], "*/")
+			if end < 0 {
+				return
+			}
+			l.move(end + 4)
+		default:
+			r, _ := utf8.DecodeRuneInString(l.src[l.pos:])
+			if !unicode.IsSpace(r) {
+				return
+			}
+			l.advance()
+		}
+	}
+}
+
+// next reads the token at the current position.
+func (l *lexer) next() (Token, error) {
+	line, col, start := l.line, l.col, l.pos
+	rest := l.src[l.pos:]
+	r, _ := utf8.DecodeRuneInString(rest)
+	token := func(kind Kind) (Token, error) {
+		return Token{Kind: kind, Text: l.src[start:l.pos], Line: line, Col: col}, nil
+	}
+	switch {
+	case strings.HasPrefix(rest, "/*"):
+		return Token{}, &Error{Line: line, Col: col, Msg: "unterminated comment"}
+	case strings.HasPrefix(rest, `"""`):
+		l.move(3)
+		for !strings.HasPrefix(l.src[l.pos:], `"""`) {
+			if l.pos >= len(l.src) {
+				return Token{}, &Error{Line: line, Col: col, Msg: "unterminated text block"}
+			}
+			if l.src[l.pos] == '\\' {
+				l.advance()
+			}
+			l.advance()
+		}
+		l.move(3)
+		return token(Literal)
+	case r == '"' || r == '\'':
+		l.advance()
+		for l.pos < len(l.src) && rune(l.src[l.pos]) != r {
+			if l.src[l.pos] == '\n' {
+				break
+			}
+			if l.src[l.pos] == '\\' {
+				l.advance()
+			}
+			l.advance()
+		}
+		if l.pos >= len(l.src) || rune(l.src[l.pos]) != r {
+			return Token{}, &Error{Line: line, Col: col, Msg: "unterminated string or character literal"}
+		}
+		l.advance()
+		return token(Literal)
+	case unicode.IsDigit(r) || (r == '.' && len(rest) > 1 && unicode.IsDigit(rune(rest[1]))):
+		for l.pos < len(l.src) {
+			c := rune(l.src[l.pos])
+			if (c == '+' || c == '-') && strings.ContainsRune("eEpP", rune(l.src[l.pos-1])) && !strings.HasPrefix(rest, "0x") {
+				l.advance()
+				continue
+			}
+			if !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != '_' && c != '.' {
+				break
+			}
+			l.advance()
+		}
+		return token(Literal)
+	case unicode.IsLetter(r) || r == '_' || r == '$':
+		for l.pos < len(l.src) {
+			c, _ := utf8.DecodeRuneInString(l.src[l.pos:])
+			if !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != '_' && c != '$' {
+				break
+			}
+			l.advance()
+		}
+		return token(Ident)
+	case strings.HasPrefix(rest, "..."):
+		l.move(3)
+		return token(Punct)
+	case strings.ContainsRune("(){}[];,.@=<>!~?:+-*/&|^%", r):
+		l.advance()
+		return token(Punct)
+	default:
+		return Token{}, &Error{Line: line, Col: col, Msg: fmt.Sprintf("unexpected character %q", r)}
+	}
+}
+
+// move advances the position by n bytes.
+func (l *lexer) move(n int) {
+	for i := 0; i < n && l.pos < len(l.src); {
+		before := l.pos
+		l.advance()
+		i += l.pos - before
+	}
+}
+
+// advance moves the position past the current character.
+func (l *lexer) advance() {
+	r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+	l.pos += size
+	if r == '\n' {
+		l.line++
+		l.col = 1
+	} else {
+		l.col++
+	}
+}
@@ -0,0 +1,48 @@
+package java
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLex_SplitsCodeIntoTokens(t *testing.T) {
+	toks, err := Lex("int x = 0x1F + 1e-3; // comment\nString s = \"a \\\" b\";")
+
+	require.NoError(t, err)
+	texts := make([]string, 0, len(toks))
+	for _, tok := range toks {
+		texts = append(texts, tok.Text)
+	}
+	assert.Equal(t, []string{"int", "x", "=", "0x1F", "+", "1e-3", ";", "String", "s", "=", `"a \" b"`, ";"}, texts)
+	assert.Equal(t, 2, toks[7].Line)
+}
+
+func TestLex_ReadsTextBlocksAndComments(t *testing.T) {
+	toks, err := Lex("/* block\n comment */ String s = \"\"\"\n  text \"quoted\"\n  \"\"\";")
+
+	require.NoError(t, err)
+	require.Len(t, toks, 5)
+	assert.Equal(t, Literal, toks[3].Kind)
+	assert.Equal(t, 2, toks[0].Line)
+}
+
+func TestLex_FailsOnUnterminatedString(t *testing.T) {
+	_, err := Lex("String s = \"abc;\nint x;")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 1, column 12")
+}
+
+func TestLex_FailsOnUnterminatedComment(t *testing.T) {
+	_, err := Lex("class Foo {} /* the rest")
+
+	assert.Error(t, err)
+}
+
+func TestLex_FailsOnMarkdownFence(t *testing.T) {
+	_, err := Lex("```java\nclass Foo {}\n```")
+
+	assert.Error(t, err)
+}
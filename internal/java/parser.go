@@ -0,0 +1,586 @@
+package java
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// File is the outline of a Java source file.
+type File struct {
+	Package string
+	// Module is the name of the module a module-info.java declares, e.g. "com.example.app".
+	Module string
+	// Imports are the imported names, e.g. "java.util.List" or "java.util.*".
+	Imports []string
+	Types   []Type
+	// Refs are the names starting with a capital letter the file mentions, i.e. the types it likely uses.
+	Refs map[string]bool
+	// Calls are the qualified method calls by the method name, e.g. "size" for "foo.size()".
+	Calls map[string][]Call
+}
+
+// Call is a qualified method call.
+type Call struct {
+	Line int
+	// Receiver is the simple name of the type of the receiver, e.g. "Foo" for "foo.size()" when foo is
+	// declared as a Foo, or "" when it can't be told, e.g. for "foo().size()".
+	Receiver string
+}
+
+// Type is a top-level class, interface, enum, record or annotation.
+type Type struct {
+	Kind string
+	Name string
+	// Supertypes are the simple names of the types it extends or implements.
+	Supertypes []string
+	// Methods are the signatures of the public methods and constructors, e.g. "add(int,List<String>)".
+	Methods []string
+	// Members are the declarations of the members that are not private, e.g. "public int size(String name)".
+	Members []string
+}
+
+// modifiers are the words that may precede a declaration.
+var modifiers = []string{
+	"public", "protected", "private", "abstract", "static", "final", "sealed", "non", "strictfp",
+	"transient", "volatile", "synchronized", "native", "default",
+}
+
+// kinds are the keywords that start a type declaration.
+var kinds = []string{"class", "interface", "enum", "record"}
+
+// parser builds the outline of a file from its tokens.
+type parser struct {
+	toks []Token
+	pos  int
+	errs []error
+}
+
+// Parse reads the outline of the Java source. It reports unbalanced brackets, text that isn't Java,
+// e.g. an explanation before or after the code, and declarations that can't be read.
+// A file without types is valid only if it declares a module, as module-info.java does.
+// All the problems found are joined into one error.
+func Parse(src string) (*File, error) {
+	toks, err := Lex(src)
+	if err != nil {
+		return nil, err
+	}
+	if err = balanced(toks); err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	file := p.file()
+	if len(p.errs) > 0 {
+		return nil, errors.Join(p.errs...)
+	}
+	if len(file.Types) == 0 && file.Module == "" {
+		return nil, &Error{Line: 1, Col: 1, Msg: "no type declaration found"}
+	}
+	file.Refs, file.Calls = usages(toks)
+	return file, nil
+}
+
+// usages finds the names of the types and the methods the tokens refer to.
+// The receivers of the calls are told by the declarations of the variables, fields and parameters
+// in the file, regardless of their scopes, a name declared with different types is left unknown.
+func usages(toks []Token) (map[string]bool, map[string][]Call) {
+	refs := make(map[string]bool)
+	calls := make(map[string][]Call)
+	vars := declarations(toks)
+	for i, t := range toks {
+		if t.Kind != Ident {
+			continue
+		}
+		if unicode.IsUpper([]rune(t.Text)[0]) {
+			refs[t.Text] = true
+		}
+		if i < 2 || toks[i-1].Text != "." || i+1 >= len(toks) || toks[i+1].Text != "(" {
+			continue
+		}
+		recv := toks[i-2]
+		if recv.Text == "this" {
+			continue
+		}
+		call := Call{Line: t.Line}
+		if recv.Kind == Ident {
+			if typ, ok := vars[recv.Text]; ok {
+				call.Receiver = typ
+			} else if unicode.IsUpper([]rune(recv.Text)[0]) {
+				call.Receiver = recv.Text
+			}
+		}
+		calls[t.Text] = append(calls[t.Text], call)
+	}
+	return refs, calls
+}
+
+// declarations maps the names of the variables, fields and parameters declared with a type
+// starting with a capital letter to the simple name of the type, e.g. "foo" to "Foo" for "Foo foo = ...".
+func declarations(toks []Token) map[string]string {
+	res := make(map[string]string)
+	for i, t := range toks {
+		if t.Kind != Ident || !unicode.IsUpper([]rune(t.Text)[0]) {
+			continue
+		}
+		j := i + 1
+		if j < len(toks) && toks[j].Text == "<" {
+			for depth := 0; j < len(toks); j++ {
+				if toks[j].Text == "<" {
+					depth++
+				} else if toks[j].Text == ">" {
+					depth--
+				}
+				if depth == 0 {
+					break
+				}
+			}
+			j++
+		}
+		for j+1 < len(toks) && toks[j].Text == "[" && toks[j+1].Text == "]" {
+			j += 2
+		}
+		if j < len(toks) && toks[j].Text == "..." {
+			j++
+		}
+		if j+1 >= len(toks) || toks[j].Kind != Ident || !slices.Contains([]string{"=", ";", ",", ")", ":"}, toks[j+1].Text) {
+			continue
+		}
+		name := toks[j].Text
+		if prev, ok := res[name]; ok && prev != t.Text {
+			res[name] = ""
+		} else {
+			res[name] = t.Text
+		}
+	}
+	return res
+}
+
+// balanced checks that every bracket is closed by the bracket of the same kind.
+func balanced(toks []Token) error {
+	pairs := map[string]string{")": "(", "]": "[", "}": "{"}
+	stack := make([]Token, 0)
+	for _, t := range toks {
+		if t.Kind != Punct {
+			continue
+		}
+		switch t.Text {
+		case "(", "[", "{":
+			stack = append(stack, t)
+		case ")", "]", "}":
+			if len(stack) == 0 {
+				return &Error{Line: t.Line, Col: t.Col, Msg: fmt.Sprintf("unexpected %q", t.Text)}
+			}
+			open := stack[len(stack)-1]
+			if open.Text != pairs[t.Text] {
+				return &Error{
+					Line: t.Line, Col: t.Col,
+					Msg: fmt.Sprintf("%q doesn't match %q opened at line %d", t.Text, open.Text, open.Line),
+				}
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	if len(stack) > 0 {
+		open := stack[len(stack)-1]
+		return &Error{Line: open.Line, Col: open.Col, Msg: fmt.Sprintf("%q is never closed, the file may be truncated", open.Text)}
+	}
+	return nil
+}
+
+// file reads the package, the imports and the top-level types.
+func (p *parser) file() *File {
+	res := &File{Imports: make([]string, 0), Types: make([]Type, 0)}
+	for p.pos < len(p.toks) {
+		t := p.toks[p.pos]
+		switch {
+		case t.Text == ";":
+			p.pos++
+		case t.Text == "package" && res.Package == "" && len(res.Types) == 0:
+			p.pos++
+			res.Package = p.joined(";")
+		case t.Text == "import":
+			p.pos++
+			if p.peek(0) == "static" {
+				p.pos++
+			}
+			res.Imports = append(res.Imports, p.joined(";"))
+		case t.Text == "@" && p.peek(1) != "interface":
+			p.annotation()
+		case (t.Text == "module" || t.Text == "open" && p.peek(1) == "module") && res.Module == "" && len(res.Types) == 0:
+			p.module(res)
+		case slices.Contains(modifiers, t.Text) || t.Text == "-":
+			p.pos++
+		case slices.Contains(kinds, t.Text) || (t.Text == "@" && p.peek(1) == "interface"):
+			res.Types = append(res.Types, p.declaration())
+		default:
+			p.fail(t, fmt.Sprintf("unexpected %q, expected a package, an import or a type declaration", t.Text))
+			return res
+		}
+	}
+	return res
+}
+
+// module reads the name of a module declaration and skips its directives, the position is at its first keyword.
+func (p *parser) module(res *File) {
+	if p.peek(0) == "open" {
+		p.pos++
+	}
+	p.pos++
+	var name strings.Builder
+	for p.pos < len(p.toks) && p.toks[p.pos].Text != "{" {
+		name.WriteString(p.toks[p.pos].Text)
+		p.pos++
+	}
+	if p.pos >= len(p.toks) || name.Len() == 0 {
+		p.fail(p.last(), "module has no name or no body")
+		return
+	}
+	res.Module = name.String()
+	p.pos = p.closing(p.pos) + 1
+}
+
+// declaration reads a type declaration, the position is at its keyword.
+func (p *parser) declaration() Type {
+	kind := p.toks[p.pos].Text
+	if kind == "@" {
+		kind = "@interface"
+		p.pos++
+	}
+	p.pos++
+	res := Type{Kind: kind, Supertypes: make([]string, 0), Methods: make([]string, 0), Members: make([]string, 0)}
+	if p.pos >= len(p.toks) || p.toks[p.pos].Kind != Ident {
+		p.fail(p.last(), fmt.Sprintf("%s has no name", kind))
+		return res
+	}
+	res.Name = p.toks[p.pos].Text
+	inherits := false
+	depth := 0
+	for p.pos < len(p.toks) && p.toks[p.pos].Text != "{" {
+		t := p.toks[p.pos]
+		switch {
+		case t.Text == "<":
+			depth++
+		case t.Text == ">":
+			depth--
+		case t.Text == "extends" || t.Text == "implements":
+			inherits = depth == 0
+		case t.Text == "permits" || t.Text == "(":
+			inherits = false
+		case inherits && depth == 0 && t.Kind == Ident && p.peek(1) != ".":
+			res.Supertypes = append(res.Supertypes, t.Text)
+		}
+		p.pos++
+	}
+	if p.pos >= len(p.toks) {
+		p.fail(p.last(), fmt.Sprintf("%s %s has no body", kind, res.Name))
+		return res
+	}
+	end := p.closing(p.pos)
+	body := &parser{toks: p.toks[p.pos+1 : end]}
+	res.Methods, res.Members = body.members(res)
+	p.errs = append(p.errs, body.errs...)
+	p.pos = end + 1
+	return res
+}
+
+// members reads the members of the type body and returns the signatures of its public methods
+// along with the declarations of all the members that are not private.
+func (p *parser) members(owner Type) ([]string, []string) {
+	res := make([]string, 0)
+	decls := make([]string, 0)
+	if owner.Kind == "enum" {
+		for p.pos < len(p.toks) && p.toks[p.pos].Text != ";" {
+			p.skip()
+		}
+		p.pos++
+	}
+	for p.pos < len(p.toks) {
+		head := make([]Token, 0)
+		assigned := false
+		for p.pos < len(p.toks) {
+			t := p.toks[p.pos]
+			if t.Text == ";" || (t.Text == "{" && !assigned) {
+				break
+			}
+			if t.Text == "=" {
+				assigned = true
+			}
+			if t.Text == "(" || t.Text == "[" || t.Text == "{" {
+				end := p.closing(p.pos)
+				head = append(head, p.toks[p.pos:end+1]...)
+				p.pos = end + 1
+				continue
+			}
+			head = append(head, t)
+			p.pos++
+		}
+		head = stripped(head)
+		if p.pos < len(p.toks) && p.toks[p.pos].Text == "{" {
+			p.pos = p.closing(p.pos) + 1
+		} else {
+			p.pos++
+		}
+		if len(head) == 0 || (len(head) == 1 && head[0].Text == "static") {
+			continue
+		}
+		if sig, public, ok := p.member(owner, head); ok && public {
+			res = append(res, sig)
+		}
+		if !slices.ContainsFunc(head, func(t Token) bool { return t.Text == "private" }) {
+			decls = append(decls, signature(head))
+		}
+	}
+	return res, decls
+}
+
+// member reads the declaration of a member from its head, i.e. the tokens before its body or semicolon.
+// For methods and constructors it returns the signature and whether the method is public.
+func (p *parser) member(owner Type, head []Token) (string, bool, bool) {
+	open := -1
+	for i, t := range head {
+		if t.Text == "=" {
+			break
+		}
+		if t.Text == "(" {
+			open = i
+			break
+		}
+		if slices.Contains(kinds, t.Text) || (t.Text == "@" && i+1 < len(head) && head[i+1].Text == "interface") {
+			return "", false, false
+		}
+	}
+	decl := head
+	if open >= 0 {
+		decl = head[:open]
+	} else if eq := slices.IndexFunc(head, func(t Token) bool { return t.Text == "=" }); eq >= 0 {
+		decl = head[:eq]
+	}
+	if !p.words(decl) {
+		return "", false, false
+	}
+	if open < 1 || head[open-1].Kind != Ident {
+		return "", false, false
+	}
+	name := head[open-1].Text
+	end := open + 1
+	for depth := 1; end < len(head) && depth > 0; end++ {
+		switch head[end].Text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		}
+	}
+	params := make([]string, 0)
+	for _, param := range split(head[open+1 : end-1]) {
+		params = append(params, typed(param))
+	}
+	public := false
+	private := false
+	for _, t := range decl {
+		public = public || t.Text == "public"
+		private = private || t.Text == "private"
+	}
+	if (owner.Kind == "interface" || owner.Kind == "@interface") && !private {
+		public = true
+	}
+	return fmt.Sprintf("%s(%s)", name, strings.Join(params, ",")), public, true
+}
+
+// words checks that the declaration looks like Java rather than prose: after the modifiers
+// it may only have a type followed by a name, so two words in a row can appear only once.
+func (p *parser) words(decl []Token) bool {
+	depth := 0
+	pairs := 0
+	prev := false
+	generic := false
+	for _, t := range decl {
+		switch {
+		case t.Text == "<":
+			if depth == 0 {
+				generic = !prev
+			}
+			depth++
+		case t.Text == ">":
+			depth--
+		}
+		word := t.Kind == Ident && !slices.Contains(modifiers, t.Text) && depth == 0
+		if word && prev {
+			pairs++
+		}
+		prev = word || (t.Text == ">" && depth == 0 && !generic) || t.Text == "]"
+		if depth == 0 && t.Kind == Punct && strings.Contains(":!?+*/&|^%~\"'", t.Text) {
+			p.fail(t, fmt.Sprintf("unexpected %q in a declaration", t.Text))
+			return false
+		}
+	}
+	if pairs > 1 {
+		p.fail(decl[0], fmt.Sprintf("unexpected text %q, it isn't a Java declaration", text(decl)))
+		return false
+	}
+	return true
+}
+
+// skip moves past the token at the current position, or past the whole block if it opens one.
+func (p *parser) skip() {
+	if t := p.toks[p.pos].Text; t == "(" || t == "[" || t == "{" {
+		p.pos = p.closing(p.pos) + 1
+		return
+	}
+	p.pos++
+}
+
+// annotation moves past an annotation, e.g. @SuppressWarnings("unused").
+func (p *parser) annotation() {
+	p.pos++
+	p.joinedName()
+	if p.peek(0) == "(" {
+		p.pos = p.closing(p.pos) + 1
+	}
+}
+
+// joined reads the tokens up to the delimiter as one string and moves past the delimiter.
+func (p *parser) joined(delim string) string {
+	var res strings.Builder
+	for p.pos < len(p.toks) && p.toks[p.pos].Text != delim {
+		res.WriteString(p.toks[p.pos].Text)
+		p.pos++
+	}
+	p.pos++
+	return res.String()
+}
+
+// joinedName reads a qualified name, e.g. java.lang.Override.
+func (p *parser) joinedName() {
+	p.pos++
+	for p.peek(0) == "." {
+		p.pos += 2
+	}
+}
+
+// closing returns the position of the bracket that closes the one at the given position.
+// The brackets are known to be balanced.
+func (p *parser) closing(pos int) int {
+	depth := 0
+	for i := pos; i < len(p.toks); i++ {
+		switch p.toks[i].Text {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(p.toks) - 1
+}
+
+// peek returns the text of the token at the offset from the current position.
+func (p *parser) peek(offset int) string {
+	if p.pos+offset >= len(p.toks) {
+		return ""
+	}
+	return p.toks[p.pos+offset].Text
+}
+
+// last returns the last token, to report problems at the end of the file.
+func (p *parser) last() Token {
+	if len(p.toks) == 0 {
+		return Token{Line: 1, Col: 1}
+	}
+	return p.toks[len(p.toks)-1]
+}
+
+// fail records a problem found at the token.
+func (p *parser) fail(t Token, msg string) {
+	p.errs = append(p.errs, &Error{Line: t.Line, Col: t.Col, Msg: msg})
+}
+
+// stripped removes annotations from the tokens, but keeps the "@interface" keyword.
+func stripped(toks []Token) []Token {
+	res := make([]Token, 0, len(toks))
+	for i := 0; i < len(toks); i++ {
+		if toks[i].Text != "@" || (i+1 < len(toks) && toks[i+1].Text == "interface") {
+			res = append(res, toks[i])
+			continue
+		}
+		i += 2
+		for i+1 < len(toks) && toks[i].Text == "." {
+			i += 2
+		}
+		if i < len(toks) && toks[i].Text == "(" {
+			depth := 0
+			for ; i < len(toks); i++ {
+				if toks[i].Text == "(" {
+					depth++
+				} else if toks[i].Text == ")" {
+					depth--
+					if depth == 0 {
+						break
+					}
+				}
+			}
+		} else {
+			i--
+		}
+	}
+	return res
+}
+
+// split splits the parameters of a method by the commas outside of type arguments.
+func split(toks []Token) [][]Token {
+	res := make([][]Token, 0)
+	if len(toks) == 0 {
+		return res
+	}
+	depth := 0
+	start := 0
+	for i, t := range toks {
+		switch t.Text {
+		case "<":
+			depth++
+		case ">":
+			depth--
+		case ",":
+			if depth == 0 {
+				res = append(res, toks[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(res, toks[start:])
+}
+
+// typed returns the type of the parameter without its name and modifiers, e.g. "List<String>" for "final List<String> names".
+func typed(param []Token) string {
+	toks := make([]Token, 0, len(param))
+	for _, t := range param {
+		if t.Text != "final" {
+			toks = append(toks, t)
+		}
+	}
+	suffix := ""
+	for len(toks) > 1 && toks[len(toks)-1].Text == "]" {
+		suffix += "[]"
+		toks = toks[:len(toks)-2]
+	}
+	if len(toks) > 1 && toks[len(toks)-1].Kind == Ident {
+		toks = toks[:len(toks)-1]
+	}
+	return text(toks) + suffix
+}
+
+// text joins the tokens without spaces between the punctuation, e.g. "Map<String,Integer>".
+func text(toks []Token) string {
+	var res strings.Builder
+	for i, t := range toks {
+		if i > 0 && t.Kind == Ident && (toks[i-1].Kind == Ident || toks[i-1].Text == "?") {
+			res.WriteString(" ")
+		}
+		res.WriteString(t.Text)
+	}
+	return res.String()
+}
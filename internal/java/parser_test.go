@@ -0,0 +1,179 @@
+package java
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sample = `/*
+ * License.
+ */
+package com.example.service;
+
+import java.util.List;
+import java.util.Map;
+
+/**
+ * Greets people.
+ */
+@Service
+public final class GreetingService<T> extends Base implements Greeter {
+    private static final Map<String, Integer> CACHE = new HashMap<>();
+    private final int[] sizes = {1, 2, 3};
+    private final Runnable task = new Runnable() {
+        @Override
+        public void run() {
+        }
+    };
+
+    static {
+        CACHE.put("a", 1);
+    }
+
+    public GreetingService(final String name) {
+        this.name = name;
+    }
+
+    @Override
+    @SuppressWarnings("unchecked")
+    public String greet(final List<Map<String, T>> people, int times, String... names) {
+        return "Hello";
+    }
+
+    public <R extends Comparable<R>> R max(R[] values, int limit[]) {
+        return values[0];
+    }
+
+    private void hidden() {
+    }
+
+    static int helper() {
+        return 0;
+    }
+
+    public static class Inner {
+        public void inner() {
+        }
+    }
+}
+`
+
+func TestParse_ReadsOutlineOfClass(t *testing.T) {
+	file, err := Parse(sample)
+
+	require.NoError(t, err)
+	assert.Equal(t, "com.example.service", file.Package)
+	require.Len(t, file.Types, 1)
+	assert.Equal(t, "class", file.Types[0].Kind)
+	assert.Equal(t, "GreetingService", file.Types[0].Name)
+	assert.Equal(
+		t,
+		[]string{"GreetingService(String)", "greet(List<Map<String,T>>,int,String...)", "max(R[],int[])"},
+		file.Types[0].Methods,
+	)
+}
+
+func TestParse_ReadsSymbolsOfClass(t *testing.T) {
+	file, err := Parse(sample)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"java.util.List", "java.util.Map"}, file.Imports)
+	assert.Equal(t, []string{"Base", "Greeter"}, file.Types[0].Supertypes)
+	assert.Equal(
+		t,
+		[]string{
+			"public GreetingService(final String name)",
+			"public String greet(final List<Map<String, T>> people, int times, String... names)",
+			"public <R extends Comparable<R>> R max(R[] values, int limit[])",
+			"static int helper()",
+			"public static class Inner",
+		},
+		file.Types[0].Members,
+	)
+	assert.True(t, file.Refs["HashMap"])
+	assert.Equal(t, []Call{{Line: 23, Receiver: "Map"}}, file.Calls["put"])
+}
+
+func TestParse_TellsReceiversOfCalls(t *testing.T) {
+	file, err := Parse(
+		"class Foo {\n  private final Bar bar;\n  void run(List<Baz>[] all, Qux... rest) {\n" +
+			"    bar.go();\n    this.bar.go();\n    Util.go();\n    make().go();\n    this.go();\n    all.go();\n  }\n}",
+	)
+
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		[]Call{{Line: 4, Receiver: "Bar"}, {Line: 5, Receiver: "Bar"}, {Line: 6, Receiver: "Util"}, {Line: 7}, {Line: 9, Receiver: "List"}},
+		file.Calls["go"],
+	)
+}
+
+func TestParse_LeavesReceiverUnknownWhenNameHasDifferentTypes(t *testing.T) {
+	file, err := Parse("class Foo {\n  void a(Bar x) {\n    x.go();\n  }\n  void b(Baz x) {\n    x.go();\n  }\n}")
+
+	require.NoError(t, err)
+	assert.Equal(t, []Call{{Line: 3}, {Line: 6}}, file.Calls["go"])
+}
+
+func TestParse_TreatsInterfaceMethodsAsPublic(t *testing.T) {
+	file, err := Parse("interface Greeter {\n  String greet(String name);\n  default void wave() {}\n  private void hide() {}\n}")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"greet(String)", "wave()"}, file.Types[0].Methods)
+}
+
+func TestParse_ReadsEnumsAndRecords(t *testing.T) {
+	file, err := Parse(
+		"public enum Color {\n  RED(1), GREEN(2) { void f() {} };\n  Color(int x) {}\n  public int code() { return 0; }\n}\n" +
+			"record Point(int x, int y) {\n  public Point {\n  }\n  public int sum() { return x + y; }\n}",
+	)
+
+	require.NoError(t, err)
+	require.Len(t, file.Types, 2)
+	assert.Equal(t, []string{"code()"}, file.Types[0].Methods)
+	assert.Equal(t, "record", file.Types[1].Kind)
+	assert.Equal(t, []string{"sum()"}, file.Types[1].Methods)
+}
+
+func TestParse_ReadsModuleInfo(t *testing.T) {
+	file, err := Parse("import java.sql.Driver;\n\nopen module com.example.app {\n  requires transitive java.sql;\n  exports com.example.app.api to com.example.web;\n  provides Driver with com.example.app.db.Fake;\n}\n")
+
+	require.NoError(t, err)
+	assert.Equal(t, "com.example.app", file.Module)
+	assert.Empty(t, file.Types)
+}
+
+func TestParse_FailsOnTruncatedFile(t *testing.T) {
+	_, err := Parse("package a;\n\npublic class Foo {\n  public void f() {\n    int x = 1;\n")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "never closed")
+}
+
+func TestParse_FailsOnExplanationBeforeCode(t *testing.T) {
+	_, err := Parse("Here is the refactored class\n\npublic class Foo {\n}")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unexpected \"Here\"")
+}
+
+func TestParse_FailsOnExplanationAfterCode(t *testing.T) {
+	_, err := Parse("public class Foo {\n}\nI inlined the variable.")
+
+	assert.Error(t, err)
+}
+
+func TestParse_FailsOnExplanationInsideClass(t *testing.T) {
+	_, err := Parse("public class Foo {\n  I removed the comment here\n  public void f() {}\n}")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+}
+
+func TestParse_FailsWithoutTypes(t *testing.T) {
+	_, err := Parse("package a;\nimport b.C;\n")
+
+	assert.Error(t, err)
+}
@@ -2,11 +2,13 @@ package protocol
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,9 +16,10 @@ import (
 
 // a2aClient represents a client for interacting with a custom API.
 type a2aClient struct {
-	url    string
-	client *http.Client
-	id     func() string
+	url       string
+	client    *http.Client
+	streaming *http.Client
+	id        func() string
 }
 
 // NewClient creates a new instance of CustomClient with a specified URL.
@@ -26,12 +29,14 @@ func NewClient(url string) Client {
 		client: &http.Client{
 			Timeout: 15 * time.Minute,
 		},
-		id: uuid.NewString,
+		streaming: &http.Client{},
+		id:        uuid.NewString,
 	}
 }
 
 // SendMessage sends a message using the custom API and returns the JSON-RPC response.
-func (c *a2aClient) SendMessage(params *MessageSendParams) (*JSONRPCResponse, error) {
+// The request is canceled along with the context.
+func (c *a2aClient) SendMessage(ctx context.Context, params *MessageSendParams) (*JSONRPCResponse, error) {
 	req := JSONRPCRequest{
 		JSONRPC: "2.0",
 		ID:      c.id(),
@@ -39,7 +44,7 @@ func (c *a2aClient) SendMessage(params *MessageSendParams) (*JSONRPCResponse, er
 		Params:  params,
 	}
 	var resp JSONRPCResponse
-	if err := c.doRequest(req, &resp); err != nil {
+	if err := c.doRequest(ctx, req, &resp); err != nil {
 		return nil, err
 	}
 	if resp.Error != nil {
@@ -49,34 +54,102 @@ func (c *a2aClient) SendMessage(params *MessageSendParams) (*JSONRPCResponse, er
 	return &resp, nil
 }
 
-// CancelTask is a placeholder for canceling a task.
-func (c *a2aClient) CancelTask() {
-	panic("unimplemented")
+// AgentCard fetches the agent card from the well-known location of the server.
+func (c *a2aClient) AgentCard() (*AgentCard, error) {
+	address := strings.TrimSuffix(c.url, "/") + "/.well-known/agent-card.json"
+	httpReq, err := http.NewRequest(http.MethodGet, address, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GET request for %s: %w", address, err)
+	}
+	httpResp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch agent card from %s: %w", address, err)
+	}
+	defer func() {
+		if err := httpResp.Body.Close(); err != nil {
+			panic(fmt.Errorf("failed to close response body: %w", err))
+		}
+	}()
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code while fetching agent card from %s: %d", address, httpResp.StatusCode)
+	}
+	var card AgentCard
+	if err := json.NewDecoder(httpResp.Body).Decode(&card); err != nil {
+		return nil, fmt.Errorf("failed to decode agent card from %s: %w", address, err)
+	}
+	return &card, nil
+}
+
+// GetTask retrieves the current state of the task with the given ID.
+func (c *a2aClient) GetTask(id string) (*Task, error) {
+	return c.task("tasks/get", TaskQueryParams{ID: id})
 }
 
-// GetTask is a placeholder for retrieving a task.
-func (c *a2aClient) GetTask() {
-	panic("unimplemented")
+// CancelTask asks the server to cancel the task with the given ID and returns the canceled task.
+func (c *a2aClient) CancelTask(id string) (*Task, error) {
+	return c.task("tasks/cancel", TaskIDParams{ID: id})
+}
+
+// SetPushNotificationConfig asks the server to send the state changes of a task to the configured URL.
+func (c *a2aClient) SetPushNotificationConfig(config *TaskPushNotificationConfig) (*TaskPushNotificationConfig, error) {
+	req := JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      c.id(),
+		Method:  "tasks/pushNotificationConfig/set",
+		Params:  config,
+	}
+	var resp JSONRPCResponse
+	if err := c.doRequest(context.Background(), req, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("%s error: '%s' (code: %d)", req.Method, resp.Error.Message, resp.Error.Code)
+	}
+	var res TaskPushNotificationConfig
+	if err := decode(resp.Result, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
 }
 
-// StreamMessage is a placeholder for streaming a message.
-func (c *a2aClient) StreamMessage() {
-	panic("unimplemented")
+// task sends a request about a task and returns the task from the response.
+func (c *a2aClient) task(method string, params any) (*Task, error) {
+	req := JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      c.id(),
+		Method:  method,
+		Params:  params,
+	}
+	var resp JSONRPCResponse
+	if err := c.doRequest(context.Background(), req, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("%s error: '%s' (code: %d)", method, resp.Error.Message, resp.Error.Code)
+	}
+	task, ok := resp.Result.(*Task)
+	if !ok {
+		return nil, fmt.Errorf("%s returned unexpected result %T", method, resp.Result)
+	}
+	return task, nil
 }
 
 // doRequest sends a JSON-RPC request to the server and decodes the response.
-func (c *a2aClient) doRequest(req any, resp *JSONRPCResponse) error {
+func (c *a2aClient) doRequest(ctx context.Context, req any, resp *JSONRPCResponse) error {
 	body, err := json.Marshal(req)
 	if err != nil {
 		return fmt.Errorf("failed to marshal request %v: %w", req, err)
 	}
-	httpReq, err := http.NewRequest("POST", c.url, bytes.NewBuffer(body))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewBuffer(body))
 	if err != nil {
 		return fmt.Errorf("failed to create POST request for %s: %w", c.url, err)
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpResp, err := c.client.Do(httpReq)
 	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("request to %s was stopped: %w", c.url, ctx.Err())
+		}
 		var netErr net.Error
 		if errors.As(err, &netErr) && netErr.Timeout() {
 			return fmt.Errorf("request to %s timed out after %s", c.url, c.client.Timeout)
@@ -1,8 +1,10 @@
 package protocol
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -17,7 +19,7 @@ func TestClient_SendsMessage(t *testing.T) {
 		Message: askJoke(),
 	}
 
-	resp, err := client.SendMessage(&message)
+	resp, err := client.SendMessage(context.Background(), &message)
 
 	require.NoError(t, err, "Failed to send message")
 	err = serv.Shutdown()
@@ -31,3 +33,96 @@ func TestClient_SendsMessage(t *testing.T) {
 	require.NotNil(t, resp, "Response should not be nil")
 	require.Equal(t, expected, resp, "Response text should match")
 }
+
+func TestClient_FetchesAgentCard(t *testing.T) {
+	serv, port := testServer(t)
+	<-serv.Ready()
+	client := NewClient(fmt.Sprintf("http://localhost:%d", port))
+
+	card, err := client.AgentCard()
+
+	require.NoError(t, err, "Failed to fetch agent card")
+	require.NoError(t, serv.Shutdown(), "Failed to close server")
+	require.Equal(t, testCard.Name, card.Name, "Agent card name should match")
+}
+
+func TestClient_PollsNonBlockingTask(t *testing.T) {
+	serv, port := streamingServer(t, joke)
+	<-serv.Ready()
+	defer func() { require.NoError(t, serv.Shutdown(), "Failed to close server") }()
+	client := NewClient(fmt.Sprintf("http://localhost:%d", port))
+	blocking := false
+
+	resp, err := client.SendMessage(context.Background(), NewMessageSendParams().
+		WithMessage(askJoke()).
+		WithConfiguration(&MessageSendConfiguration{Blocking: &blocking}))
+
+	require.NoError(t, err, "Failed to send message")
+	task, ok := resp.Result.(*Task)
+	require.True(t, ok, "Non-blocking request should return a task")
+	require.Eventually(t, func() bool {
+		current, gerr := client.GetTask(task.ID)
+		return gerr == nil && current.Status.State == TaskStateCompleted
+	}, 5*time.Second, 10*time.Millisecond, "Task should complete")
+	done, err := client.GetTask(task.ID)
+	require.NoError(t, err)
+	require.Equal(t, tellJoke().Text(), done.Status.Message.Text(), "Task should keep the answer")
+}
+
+func TestClient_CancelsRunningTask(t *testing.T) {
+	stopped := make(chan error, 1)
+	serv, port := streamingServer(t, func(ctx context.Context, _ *Message) (*Message, error) {
+		<-ctx.Done()
+		stopped <- ctx.Err()
+		return nil, ctx.Err()
+	})
+	<-serv.Ready()
+	defer func() { require.NoError(t, serv.Shutdown(), "Failed to close server") }()
+	client := NewClient(fmt.Sprintf("http://localhost:%d", port))
+	blocking := false
+	resp, err := client.SendMessage(context.Background(), NewMessageSendParams().
+		WithMessage(askJoke()).
+		WithConfiguration(&MessageSendConfiguration{Blocking: &blocking}))
+	require.NoError(t, err, "Failed to send message")
+
+	canceled, err := client.CancelTask(resp.Result.(*Task).ID)
+
+	require.NoError(t, err, "Failed to cancel task")
+	require.Equal(t, TaskStateCanceled, canceled.Status.State)
+	require.ErrorIs(t, <-stopped, context.Canceled, "Cancellation should reach the handler")
+}
+
+func TestClient_FailsToGetUnknownTask(t *testing.T) {
+	serv, port := testServer(t)
+	<-serv.Ready()
+	defer func() { require.NoError(t, serv.Shutdown(), "Failed to close server") }()
+	client := NewClient(fmt.Sprintf("http://localhost:%d", port))
+
+	_, err := client.GetTask("unknown")
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "-32001")
+}
+
+func TestClient_CancelsMessageWithContext(t *testing.T) {
+	stopped := make(chan struct{})
+	serv, port := streamingServer(t, func(ctx context.Context, _ *Message) (*Message, error) {
+		<-ctx.Done()
+		close(stopped)
+		return nil, ctx.Err()
+	})
+	<-serv.Ready()
+	defer func() { require.NoError(t, serv.Shutdown(), "Failed to close server") }()
+	client := NewClient(fmt.Sprintf("http://localhost:%d", port))
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := client.SendMessage(ctx, NewMessageSendParams().WithMessage(askJoke()))
+
+	require.ErrorIs(t, err, context.DeadlineExceeded, "Request should stop with the context")
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "Handler should be stopped when the client cancels the request")
+	}
+}
@@ -3,12 +3,14 @@ package protocol
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"time"
 
 	"github.com/cqfn/refrax/internal/log"
+	"github.com/google/uuid"
 )
 
 type a2aServer struct {
@@ -18,8 +20,11 @@ type a2aServer struct {
 	port       int
 	server     *http.Server
 	handler    Handler
+	ctx        context.Context
 	cancel     context.CancelFunc
 	ready      chan bool
+	tasks      *tracker
+	notifier   *notifier
 }
 
 // NewServer creates a new instance of a custom server that handles A2A requests
@@ -31,8 +36,10 @@ func NewServer(card *AgentCard, port int) Server {
 		card:       *card,
 		port:       port,
 		msgHandler: record,
+		ctx:        ctx,
 		cancel:     cancel,
 		ready:      make(chan bool, 1),
+		notifier:   newNotifier(),
 		server: &http.Server{
 			Addr:              fmt.Sprintf(":%d", port),
 			Handler:           mux,
@@ -40,6 +47,7 @@ func NewServer(card *AgentCard, port int) Server {
 			BaseContext:       func(_ net.Listener) context.Context { return ctx },
 		},
 	}
+	server.tasks = newTracker(NewInMemoryTaskStore(), server.changed)
 	mux.HandleFunc("/.well-known/agent-card.json", server.handleAgentCard)
 	mux.HandleFunc("/", server.handleRequest)
 	return server
@@ -55,6 +63,12 @@ func (serv *a2aServer) Handler(handler Handler) {
 	serv.handler = handler
 }
 
+// TaskStore sets the store that keeps the tasks of the server.
+// It must be set before the server starts.
+func (serv *a2aServer) TaskStore(store TaskStore) {
+	serv.tasks = newTracker(store, serv.changed)
+}
+
 // ListenAndServe starts the custom server and listens on the specified port, while signaling readiness.
 func (serv *a2aServer) ListenAndServe() error {
 	log.Debug("Starting custom a2a server on port %d...", serv.port)
@@ -64,6 +78,7 @@ func (serv *a2aServer) ListenAndServe() error {
 		return fmt.Errorf("failed to listen on port %d: %w", serv.port, err)
 	}
 	close(serv.ready)
+	go serv.notifier.run(serv.ctx)
 	if err = serv.server.Serve(l); err != nil && err != http.ErrServerClosed {
 		return fmt.Errorf("failed to start server on port %d: %w", serv.port, err)
 	}
@@ -110,14 +125,28 @@ func (serv *a2aServer) handleJSONRPC(w http.ResponseWriter, r *http.Request) err
 		resp := failure("", ErrCodeInvalidRequest, "Invalid JSON payload")
 		return send(w, &resp)
 	}
+	next := serv.basic(r.Context())
+	streamed := false
+	if req.Method == "message/stream" {
+		next = func(_ Handler, sreq *JSONRPCRequest) (*JSONRPCResponse, error) {
+			resp, started, err := serv.stream(w, r, sreq)
+			streamed = started
+			return resp, err
+		}
+	}
 	var resp *JSONRPCResponse
 	var err error
 	if serv.handler != nil {
 		start := serv.handler
-		resp, err = start(basic(r.Context(), serv.msgHandler), &req)
+		resp, err = start(next, &req)
 	} else {
-		start := basic(r.Context(), serv.msgHandler)
-		resp, err = start(nil, &req)
+		resp, err = next(nil, &req)
+	}
+	if streamed {
+		if err != nil {
+			log.Warn("Failed to stream the response: %v", err)
+		}
+		return nil
 	}
 	if err != nil {
 		resp := failure(str(req.ID), ErrCodeInternalError, fmt.Sprintf("Failed to handle request: %v", err))
@@ -126,38 +155,82 @@ func (serv *a2aServer) handleJSONRPC(w http.ResponseWriter, r *http.Request) err
 	return send(w, resp)
 }
 
-func basic(ctx context.Context, mh MsgHandler) Handler {
+func (serv *a2aServer) basic(ctx context.Context) Handler {
 	return func(_ Handler, r *JSONRPCRequest) (*JSONRPCResponse, error) {
 		id := str(r.ID)
 		switch r.Method {
 		case "message/send":
-			pbytes, err := json.Marshal(r.Params)
 			var params MessageSendParams
-			if err != nil {
-				msg := fmt.Sprintf("Failed to marshal params '%v': %v", r.Params, err)
-				resp := failure(id, ErrCodeInvalidRequest, msg)
+			if err := decode(r.Params, &params); err != nil {
+				resp := failure(id, ErrCodeInvalidRequest, err.Error())
 				return &resp, nil
 			}
-			if err = json.Unmarshal(pbytes, &params); err != nil {
-				msg := fmt.Sprintf("Failed to unmarshal params '%v': : %v", r.Params, err)
-				resp := failure(id, ErrCodeInvalidRequest, msg)
+			log.Debug("Handling JSON-RPC request: %s, params: %v", r.Method, params)
+			return serv.sendMessage(ctx, id, &params), nil
+		case "tasks/get":
+			var params TaskQueryParams
+			if err := decode(r.Params, &params); err != nil {
+				resp := failure(id, ErrCodeInvalidParams, err.Error())
 				return &resp, nil
 			}
-			log.Debug("Handling JSON-RPC request: %s, params: %v", r.Method, params)
-			msg := params.Message
-			msg, err = mh(ctx, msg)
+			task, err := serv.tasks.get(params.ID, params.HistoryLength)
+			return taskResponse(id, task, err), nil
+		case "tasks/cancel":
+			var params TaskIDParams
+			if err := decode(r.Params, &params); err != nil {
+				resp := failure(id, ErrCodeInvalidParams, err.Error())
+				return &resp, nil
+			}
+			task, err := serv.tasks.cancel(params.ID)
+			return taskResponse(id, task, err), nil
+		case "tasks/pushNotificationConfig/set":
+			var params TaskPushNotificationConfig
+			if err := decode(r.Params, &params); err != nil {
+				resp := failure(id, ErrCodeInvalidParams, err.Error())
+				return &resp, nil
+			}
+			var config *TaskPushNotificationConfig
+			err := serv.tasks.active(params.TaskID, func() error {
+				var serr error
+				config, serr = serv.notifier.set(params.TaskID, params.PushNotificationConfig)
+				return serr
+			})
+			return configResponse(id, config, err), nil
+		case "tasks/pushNotificationConfig/get":
+			var params GetTaskPushNotificationConfigParams
+			if err := decode(r.Params, &params); err != nil {
+				resp := failure(id, ErrCodeInvalidParams, err.Error())
+				return &resp, nil
+			}
+			config, err := serv.notifier.get(params.ID, params.PushNotificationConfigID)
+			return configResponse(id, config, err), nil
+		case "tasks/pushNotificationConfig/list":
+			var params ListTaskPushNotificationConfigParams
+			if err := decode(r.Params, &params); err != nil {
+				resp := failure(id, ErrCodeInvalidParams, err.Error())
+				return &resp, nil
+			}
+			var configs []TaskPushNotificationConfig
+			err := serv.tasks.active(params.ID, func() error {
+				configs = serv.notifier.list(params.ID)
+				return nil
+			})
 			if err != nil {
-				resp := failure(id, ErrCodeInternalError, fmt.Sprintf("failed to handle message send: %v", err))
+				return configResponse(id, nil, err), nil
+			}
+			resp := success(id, configs)
+			return &resp, nil
+		case "tasks/pushNotificationConfig/delete":
+			var params DeleteTaskPushNotificationConfigParams
+			if err := decode(r.Params, &params); err != nil {
+				resp := failure(id, ErrCodeInvalidParams, err.Error())
 				return &resp, nil
 			}
-			resp := success(id, msg)
+			if err := serv.notifier.remove(params.ID, params.PushNotificationConfigID); err != nil {
+				return configResponse(id, nil, err), nil
+			}
+			resp := success(id, nil)
 			return &resp, nil
-		case "message/stream":
-			panic("message/stream is not implemented yet")
-		case "tasks/get":
-			panic("tasks/get is not implemented yet")
-		case "tasks/cancel":
-			panic("tasks/get is not implemented yet")
 		default:
 			resp := failure(id, ErrCodeMethodNotFound, "Method not found")
 			return &resp, nil
@@ -165,6 +238,110 @@ func basic(ctx context.Context, mh MsgHandler) Handler {
 	}
 }
 
+// sendMessage handles the message/send request.
+// A blocking request waits for the answer of the agent, a non-blocking one returns the submitted task
+// right away and the agent handles the message in the background until the server shuts down.
+func (serv *a2aServer) sendMessage(ctx context.Context, id string, params *MessageSendParams) *JSONRPCResponse {
+	if params.Message == nil {
+		resp := failure(id, ErrCodeInvalidParams, "Message is required")
+		return &resp
+	}
+	wait := blocking(params.Configuration)
+	if !wait {
+		ctx = serv.ctx
+	}
+	task, tctx, err := serv.tasks.start(ctx, params.Message)
+	if err != nil {
+		resp := failure(id, ErrCodeInvalidRequest, err.Error())
+		return &resp
+	}
+	if params.Configuration != nil && params.Configuration.PushNotificationConfig != nil {
+		if _, err = serv.notifier.set(task.ID, *params.Configuration.PushNotificationConfig); err != nil {
+			log.Warn("Task %s will not send push notifications: %v", task.ID, err)
+		}
+	}
+	if !wait {
+		go func() {
+			if _, err := serv.execute(tctx, task.ID, params.Message); err != nil {
+				log.Warn("Task %s failed: %v", task.ID, err)
+			}
+		}()
+		resp := success(id, task)
+		return &resp
+	}
+	msg, err := serv.execute(tctx, task.ID, params.Message)
+	if err != nil {
+		resp := failure(id, ErrCodeInternalError, fmt.Sprintf("failed to handle message send: %v", err))
+		return &resp
+	}
+	resp := success(id, msg)
+	return &resp
+}
+
+// execute runs the message handler within the task and records the outcome in the task store.
+func (serv *a2aServer) execute(ctx context.Context, taskID string, msg *Message) (*Message, error) {
+	if _, err := serv.tasks.update(taskID, TaskStateWorking, nil); err != nil {
+		return nil, err
+	}
+	answer, err := serv.msgHandler(ctx, msg)
+	if err != nil {
+		if _, uerr := serv.tasks.update(taskID, TaskStateFailed, reason(err.Error())); uerr != nil {
+			log.Warn("Failed to mark task %s as failed: %v", taskID, uerr)
+		}
+		return nil, err
+	}
+	if _, err = serv.tasks.complete(taskID, answer); err != nil {
+		log.Warn("Failed to mark task %s as completed: %v", taskID, err)
+	}
+	return answer, nil
+}
+
+// changed is called on every state change of a task.
+func (serv *a2aServer) changed(task *Task) {
+	serv.notifier.notify(serv.ctx, task)
+}
+
+// blocking reports whether the client waits for the answer, that is the default.
+func blocking(cfg *MessageSendConfiguration) bool {
+	return cfg == nil || cfg.Blocking == nil || *cfg.Blocking
+}
+
+func taskResponse(id string, task *Task, err error) *JSONRPCResponse {
+	var resp JSONRPCResponse
+	switch {
+	case errors.Is(err, ErrTaskNotFound):
+		resp = failure(id, ErrCodeTaskNotFound, err.Error())
+	case errors.Is(err, errNotCancelable):
+		resp = failure(id, ErrCodeTaskNotCancelable, err.Error())
+	case err != nil:
+		resp = failure(id, ErrCodeInternalError, err.Error())
+	default:
+		resp = success(id, task)
+	}
+	return &resp
+}
+
+func configResponse(id string, config *TaskPushNotificationConfig, err error) *JSONRPCResponse {
+	var resp JSONRPCResponse
+	switch {
+	case errors.Is(err, ErrTaskNotFound):
+		resp = failure(id, ErrCodeTaskNotFound, err.Error())
+	case err != nil:
+		resp = failure(id, ErrCodeInvalidParams, err.Error())
+	default:
+		resp = success(id, config)
+	}
+	return &resp
+}
+
+// reason creates an agent message that explains the status of a task.
+func reason(text string) *Message {
+	return NewMessage().
+		WithRole("agent").
+		WithMessageID(uuid.NewString()).
+		AddPart(NewText(text))
+}
+
 // str converts various types to a string representation.
 func str(v any) string {
 	switch val := v.(type) {
@@ -0,0 +1,146 @@
+package protocol
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"strings"
+)
+
+// maxEventSize is the maximum size of a single Server-Sent Event, events carry whole files.
+const maxEventSize = 64 * 1024 * 1024
+
+// ErrTaskCanceled is returned by Await when the task of the stream is canceled.
+var ErrTaskCanceled = errors.New("task was canceled")
+
+// StreamMessage sends a message with message/stream and returns the events the server streams back.
+// The iteration stops after the final status update, on the first error or when the caller breaks the loop.
+func (c *a2aClient) StreamMessage(params *MessageSendParams) iter.Seq2[*JSONRPCResponse, error] {
+	return func(yield func(*JSONRPCResponse, error) bool) {
+		req := JSONRPCRequest{
+			JSONRPC: "2.0",
+			ID:      c.id(),
+			Method:  "message/stream",
+			Params:  params,
+		}
+		body, err := json.Marshal(req)
+		if err != nil {
+			yield(nil, fmt.Errorf("failed to marshal request %v: %w", req, err))
+			return
+		}
+		httpReq, err := http.NewRequest(http.MethodPost, c.url, bytes.NewBuffer(body))
+		if err != nil {
+			yield(nil, fmt.Errorf("failed to create POST request for %s: %w", c.url, err))
+			return
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("Accept", "text/event-stream")
+		httpResp, err := c.streaming.Do(httpReq)
+		if err != nil {
+			yield(nil, fmt.Errorf("failed to send request '%v': %w", req, err))
+			return
+		}
+		defer func() {
+			if err := httpResp.Body.Close(); err != nil {
+				panic(fmt.Errorf("failed to close response body: %w", err))
+			}
+		}()
+		if httpResp.StatusCode != http.StatusOK {
+			yield(nil, fmt.Errorf("unexpected status code: %d", httpResp.StatusCode))
+			return
+		}
+		if !strings.HasPrefix(httpResp.Header.Get("Content-Type"), "text/event-stream") {
+			var resp JSONRPCResponse
+			if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+				yield(nil, fmt.Errorf("failed to decode response: %w", err))
+				return
+			}
+			yield(&resp, streamErr(&resp))
+			return
+		}
+		events(httpResp, yield)
+	}
+}
+
+// events reads Server-Sent Events from the response and yields them one by one.
+func events(httpResp *http.Response, yield func(*JSONRPCResponse, error) bool) {
+	scanner := bufio.NewScanner(httpResp.Body)
+	scanner.Buffer(make([]byte, 64*1024), maxEventSize)
+	data := make([]string, 0)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line != "" {
+			if payload, ok := strings.CutPrefix(line, "data:"); ok {
+				data = append(data, strings.TrimPrefix(payload, " "))
+			}
+			continue
+		}
+		if len(data) == 0 {
+			continue
+		}
+		var resp JSONRPCResponse
+		if err := json.Unmarshal([]byte(strings.Join(data, "\n")), &resp); err != nil {
+			yield(nil, fmt.Errorf("failed to decode event: %w", err))
+			return
+		}
+		data = data[:0]
+		if err := streamErr(&resp); err != nil {
+			yield(nil, err)
+			return
+		}
+		if !yield(&resp, nil) {
+			return
+		}
+		if update, ok := resp.Result.(*TaskStatusUpdateEvent); ok && update.Final {
+			return
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		yield(nil, fmt.Errorf("failed to read event stream: %w", err))
+	}
+}
+
+func streamErr(resp *JSONRPCResponse) error {
+	if resp.Error != nil {
+		return fmt.Errorf("message/stream error: '%s' (code: %d)", resp.Error.Message, resp.Error.Code)
+	}
+	return nil
+}
+
+// Await consumes the stream and returns the message the agent answered with.
+// Every status message received on the way is passed to the progress callback.
+// It fails if the task fails, if it is canceled, with ErrTaskCanceled, or if the stream ends without an answer.
+func Await(stream iter.Seq2[*JSONRPCResponse, error], progress func(text string)) (*Message, error) {
+	var answer *Message
+	for resp, err := range stream {
+		if err != nil {
+			return nil, err
+		}
+		switch event := resp.Result.(type) {
+		case *Message:
+			answer = event
+		case *TaskStatusUpdateEvent:
+			text := ""
+			if event.Status.Message != nil {
+				text = event.Status.Message.Text()
+			}
+			switch event.Status.State {
+			case TaskStateFailed:
+				return nil, fmt.Errorf("task %s failed: %s", event.TaskID, text)
+			case TaskStateCanceled:
+				return nil, fmt.Errorf("task %s: %w", event.TaskID, ErrTaskCanceled)
+			}
+			if text != "" {
+				progress(text)
+			}
+		}
+	}
+	if answer == nil {
+		return nil, fmt.Errorf("stream ended without an answer")
+	}
+	return answer, nil
+}
@@ -0,0 +1,108 @@
+package protocol
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cqfn/refrax/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_StreamsMessage(t *testing.T) {
+	serv, port := streamingServer(t, func(ctx context.Context, msg *Message) (*Message, error) {
+		Progress(ctx, "thinking about a joke")
+		return joke(ctx, msg)
+	})
+	<-serv.Ready()
+	defer func() { require.NoError(t, serv.Shutdown(), "Failed to close server") }()
+	client := NewClient(fmt.Sprintf("http://localhost:%d", port))
+	progress := make([]string, 0)
+
+	answer, err := Await(client.StreamMessage(&MessageSendParams{Message: askJoke()}), func(text string) {
+		progress = append(progress, text)
+	})
+
+	require.NoError(t, err, "Failed to stream message")
+	assert.Equal(t, tellJoke().Text(), answer.Text(), "Answer text should match")
+	assert.NotEmpty(t, answer.TaskID, "Answer should belong to a task")
+	assert.Equal(t, []string{"thinking about a joke"}, progress, "Progress should be streamed")
+}
+
+func TestClient_StreamsFailure(t *testing.T) {
+	serv, port := streamingServer(t, func(_ context.Context, _ *Message) (*Message, error) {
+		return nil, fmt.Errorf("no jokes today")
+	})
+	<-serv.Ready()
+	defer func() { require.NoError(t, serv.Shutdown(), "Failed to close server") }()
+	client := NewClient(fmt.Sprintf("http://localhost:%d", port))
+
+	_, err := Await(client.StreamMessage(&MessageSendParams{Message: askJoke()}), func(string) {})
+
+	require.Error(t, err, "Failure should be streamed")
+	assert.Contains(t, err.Error(), "no jokes today")
+}
+
+func TestClient_StreamsFinalStatusLast(t *testing.T) {
+	serv, port := testServer(t)
+	<-serv.Ready()
+	defer func() { require.NoError(t, serv.Shutdown(), "Failed to close server") }()
+	client := NewClient(fmt.Sprintf("http://localhost:%d", port))
+	kinds := make([]string, 0)
+
+	for resp, err := range client.StreamMessage(&MessageSendParams{Message: askJoke()}) {
+		require.NoError(t, err, "Failed to read event")
+		switch event := resp.Result.(type) {
+		case *Message:
+			kinds = append(kinds, string(event.Kind))
+		case *TaskStatusUpdateEvent:
+			kinds = append(kinds, string(event.Status.State))
+		}
+	}
+
+	assert.Equal(t, []string{"working", string(KindMessage), "completed"}, kinds, "Events should come in order")
+}
+
+func TestServer_StreamsThroughHandler(t *testing.T) {
+	serv, port := streamingServer(t, joke)
+	var calls atomic.Int32
+	serv.Handler(func(next Handler, r *JSONRPCRequest) (*JSONRPCResponse, error) {
+		calls.Add(1)
+		return next(nil, r)
+	})
+	<-serv.Ready()
+	defer func() { require.NoError(t, serv.Shutdown(), "Failed to close server") }()
+	client := NewClient(fmt.Sprintf("http://localhost:%d", port))
+
+	answer, err := Await(client.StreamMessage(&MessageSendParams{Message: askJoke()}), func(string) {})
+
+	require.NoError(t, err, "Failed to stream message")
+	assert.Equal(t, tellJoke().Text(), answer.Text(), "Answer text should match")
+	assert.Equal(t, int32(1), calls.Load(), "Streamed request should pass through the handler")
+}
+
+func TestAwait_ReportsCanceledTask(t *testing.T) {
+	stream := iter.Seq2[*JSONRPCResponse, error](func(yield func(*JSONRPCResponse, error) bool) {
+		resp := success("1", NewStatusUpdate("task-1", "ctx-1", TaskStateCanceled, nil, true))
+		yield(&resp, nil)
+	})
+
+	_, err := Await(stream, func(string) {})
+
+	require.ErrorIs(t, err, ErrTaskCanceled, "Canceled task should be reported as such")
+}
+
+func streamingServer(t *testing.T, handler MsgHandler) (server Server, port int) {
+	t.Helper()
+	port, err := util.FreePort()
+	require.NoError(t, err, "Failed to get a free port")
+	server = NewServer(&testCard, port)
+	server.MsgHandler(handler)
+	go func() {
+		_ = server.ListenAndServe()
+	}()
+	return server, port
+}
@@ -1,9 +1,16 @@
 package protocol
 
+import (
+	"context"
+	"iter"
+)
+
 // Client represents the interface for a protocol client.
 type Client interface {
-	SendMessage(question *MessageSendParams) (*JSONRPCResponse, error)
-	StreamMessage()
-	GetTask()
-	CancelTask()
+	AgentCard() (*AgentCard, error)
+	SendMessage(ctx context.Context, question *MessageSendParams) (*JSONRPCResponse, error)
+	StreamMessage(question *MessageSendParams) iter.Seq2[*JSONRPCResponse, error]
+	GetTask(id string) (*Task, error)
+	CancelTask(id string) (*Task, error)
+	SetPushNotificationConfig(config *TaskPushNotificationConfig) (*TaskPushNotificationConfig, error)
 }
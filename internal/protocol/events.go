@@ -0,0 +1,46 @@
+package protocol
+
+// TaskStatusUpdateEvent notifies the client about a change of the task status during streaming.
+type TaskStatusUpdateEvent struct {
+	TaskID    string         `json:"taskId"`             // Required: ID of the task
+	ContextID string         `json:"contextId"`          // Required: context the task belongs to
+	Kind      Kind           `json:"kind"`               // Required: must be "status-update"
+	Status    TaskStatus     `json:"status"`             // Required: new status of the task
+	Final     bool           `json:"final"`              // Required: true if this is the last event of the stream
+	Metadata  map[string]any `json:"metadata,omitempty"` // Optional: extension metadata
+}
+
+// TaskArtifactUpdateEvent notifies the client about an artifact produced by the task during streaming.
+type TaskArtifactUpdateEvent struct {
+	TaskID    string         `json:"taskId"`              // Required: ID of the task
+	ContextID string         `json:"contextId"`           // Required: context the task belongs to
+	Kind      Kind           `json:"kind"`                // Required: must be "artifact-update"
+	Artifact  Artifact       `json:"artifact"`            // Required: the produced artifact
+	Append    *bool          `json:"append,omitempty"`    // Optional: append to a previously sent artifact
+	LastChunk *bool          `json:"lastChunk,omitempty"` // Optional: this is the last chunk of the artifact
+	Metadata  map[string]any `json:"metadata,omitempty"`  // Optional: extension metadata
+}
+
+// NewStatusUpdate creates a status update event for the given task.
+func NewStatusUpdate(taskID, contextID string, state TaskState, msg *Message, final bool) *TaskStatusUpdateEvent {
+	return &TaskStatusUpdateEvent{
+		TaskID:    taskID,
+		ContextID: contextID,
+		Kind:      KindStatusUpdate,
+		Status: TaskStatus{
+			State:   state,
+			Message: msg,
+		},
+		Final: final,
+	}
+}
+
+// NewArtifactUpdate creates an artifact update event for the given task.
+func NewArtifactUpdate(taskID, contextID string, artifact *Artifact) *TaskArtifactUpdateEvent {
+	return &TaskArtifactUpdateEvent{
+		TaskID:    taskID,
+		ContextID: contextID,
+		Kind:      KindArtifactUpdate,
+		Artifact:  *artifact,
+	}
+}
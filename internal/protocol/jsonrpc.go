@@ -24,6 +24,12 @@ const (
 	// -32603: Internal server error
 	ErrCodeInternalError = -32603
 
+	// -32001: Task not found (A2A-specific)
+	ErrCodeTaskNotFound = -32001
+
+	// -32002: Task cannot be canceled (A2A-specific)
+	ErrCodeTaskNotCancelable = -32002
+
 	// -32000 to -32099: Reserved for server-defined errors (A2A-specific)
 	ErrCodeServerErrorStart = -32099
 	ErrCodeServerErrorEnd   = -32000
@@ -73,6 +79,14 @@ func (r *JSONRPCResponse) UnmarshalJSON(data []byte) error {
 		r.Result = nil
 		return nil
 	}
+	if aux.Result[0] == '[' {
+		var list []any
+		if err := json.Unmarshal(aux.Result, &list); err != nil {
+			return fmt.Errorf("failed to unmarshal list result: %w", err)
+		}
+		r.Result = list
+		return nil
+	}
 	var kind struct {
 		Kind string `json:"kind"`
 	}
@@ -93,6 +107,18 @@ func (r *JSONRPCResponse) UnmarshalJSON(data []byte) error {
 			return fmt.Errorf("failed to unmarshal task: %w", err)
 		}
 		r.Result = &task
+	case "status-update":
+		var event TaskStatusUpdateEvent
+		if err := json.Unmarshal(aux.Result, &event); err != nil {
+			return fmt.Errorf("failed to unmarshal status update: %w", err)
+		}
+		r.Result = &event
+	case "artifact-update":
+		var event TaskArtifactUpdateEvent
+		if err := json.Unmarshal(aux.Result, &event); err != nil {
+			return fmt.Errorf("failed to unmarshal artifact update: %w", err)
+		}
+		r.Result = &event
 	default:
 		var generic map[string]any
 		if err := json.Unmarshal(aux.Result, &generic); err != nil {
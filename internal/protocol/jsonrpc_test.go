@@ -46,3 +46,22 @@ func TestJSONRPCResponse_UnmarshalTask(t *testing.T) {
 	require.NoError(t, err, "Failed to unmarshal JSONRPCResponse")
 	assert.Equal(t, before, after, "data structures should match")
 }
+
+func TestJSONRPCResponse_UnmarshalStatusUpdate(t *testing.T) {
+	msg := NewMessage().
+		WithMessageID("5d3c8a80-36c1-4d1c-9c4e-3d56c1a1b9c0").
+		WithRole("agent").
+		AddPart(NewText("still thinking"))
+	before := JSONRPCResponse{
+		ID:     float64(1),
+		Result: NewStatusUpdate("12345", "67890", TaskStateWorking, msg, false),
+	}
+	var after JSONRPCResponse
+	data, err := json.Marshal(before)
+	require.NoError(t, err, "Failed to marshal JSONRPCResponse")
+
+	err = json.Unmarshal(data, &after)
+
+	require.NoError(t, err, "Failed to unmarshal JSONRPCResponse")
+	assert.Equal(t, before, after, "data structures should match")
+}
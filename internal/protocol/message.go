@@ -1,5 +1,7 @@
 package protocol
 
+import "strings"
+
 // Kind represents the type of a protocol entity, such as a task or a message.
 type Kind string
 
@@ -9,6 +11,12 @@ const (
 
 	// KindMessage represents a message entity.
 	KindMessage Kind = "message"
+
+	// KindStatusUpdate represents a task status update event sent during streaming.
+	KindStatusUpdate Kind = "status-update"
+
+	// KindArtifactUpdate represents a task artifact update event sent during streaming.
+	KindArtifactUpdate Kind = "artifact-update"
 )
 
 // Message represents a communication unit between a user and an agent.
@@ -70,6 +78,17 @@ func (m *Message) AddReferenceTaskIDs(ids ...string) *Message {
 	return m
 }
 
+// Text returns the content of all text parts of the Message joined by new lines.
+func (m *Message) Text() string {
+	texts := make([]string, 0, len(m.Parts))
+	for _, part := range m.Parts {
+		if tp, ok := part.(*TextPart); ok {
+			texts = append(texts, tp.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
 // WithTaskID sets the TaskID field of the Message and returns the updated Message instance.
 func (m *Message) WithTaskID(taskID string) *Message {
 	m.TaskID = &taskID
@@ -122,14 +122,25 @@ func (c *AgentCard) WithDefaultOutputModes(modes []string) *AgentCard {
 }
 
 // AddSkill appends a single skill to the agent's skill list.
-func (c *AgentCard) AddSkill(_, name, description string) *AgentCard {
+func (c *AgentCard) AddSkill(id, name, description string) *AgentCard {
 	c.Skills = append(c.Skills, AgentSkill{
+		ID:          id,
 		Name:        name,
 		Description: description,
 	})
 	return c
 }
 
+// HasSkill checks whether the agent declares a skill with the given ID.
+func (c *AgentCard) HasSkill(id string) bool {
+	for _, s := range c.Skills {
+		if s.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
 // WithSkills sets the list of skills for the agent.
 func (c *AgentCard) WithSkills(skills []AgentSkill) *AgentCard {
 	c.Skills = skills
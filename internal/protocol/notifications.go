@@ -19,3 +19,23 @@ type TaskPushNotificationConfig struct {
 	TaskID                 string                 `json:"taskId"`
 	PushNotificationConfig PushNotificationConfig `json:"pushNotificationConfig"`
 }
+
+// GetTaskPushNotificationConfigParams defines the parameters of the tasks/pushNotificationConfig/get request.
+type GetTaskPushNotificationConfigParams struct {
+	ID                       string         `json:"id"`                                 // Required: task ID
+	PushNotificationConfigID *string        `json:"pushNotificationConfigId,omitempty"` // Optional: the first config is used if empty
+	Metadata                 map[string]any `json:"metadata,omitempty"`                 // Optional: extension metadata
+}
+
+// ListTaskPushNotificationConfigParams defines the parameters of the tasks/pushNotificationConfig/list request.
+type ListTaskPushNotificationConfigParams struct {
+	ID       string         `json:"id"`                 // Required: task ID
+	Metadata map[string]any `json:"metadata,omitempty"` // Optional: extension metadata
+}
+
+// DeleteTaskPushNotificationConfigParams defines the parameters of the tasks/pushNotificationConfig/delete request.
+type DeleteTaskPushNotificationConfigParams struct {
+	ID                       string         `json:"id"`                       // Required: task ID
+	PushNotificationConfigID string         `json:"pushNotificationConfigId"` // Required: config to delete
+	Metadata                 map[string]any `json:"metadata,omitempty"`       // Optional: extension metadata
+}
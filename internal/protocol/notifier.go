@@ -0,0 +1,198 @@
+package protocol
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cqfn/refrax/internal/log"
+	"github.com/google/uuid"
+)
+
+// ErrPushConfigNotFound is returned when a task has no push notification config with the requested ID.
+var ErrPushConfigNotFound = errors.New("push notification config not found")
+
+// notification is a task state change that must be delivered to a single push notification URL.
+type notification struct {
+	config PushNotificationConfig
+	task   Task
+}
+
+// notifier keeps the push notification configs of tasks and POSTs task state changes to them.
+// Notifications are delivered one by one in the order of the state changes.
+type notifier struct {
+	mu      sync.Mutex
+	configs map[string][]PushNotificationConfig
+	queue   chan notification
+	client  *http.Client
+}
+
+func newNotifier() *notifier {
+	return &notifier{
+		configs: make(map[string][]PushNotificationConfig),
+		queue:   make(chan notification, 64),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// run delivers the queued notifications until the context is canceled.
+func (n *notifier) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case next := <-n.queue:
+			if err := n.deliver(ctx, next); err != nil {
+				log.Warn("Failed to notify %s about task %s: %v", next.config.URL, next.task.ID, err)
+			}
+		}
+	}
+}
+
+// set adds the config to the task or replaces the config with the same ID.
+func (n *notifier) set(taskID string, config PushNotificationConfig) (*TaskPushNotificationConfig, error) {
+	if err := validate(config); err != nil {
+		return nil, err
+	}
+	if config.ID == nil || *config.ID == "" {
+		id := uuid.NewString()
+		config.ID = &id
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	configs := n.configs[taskID]
+	replaced := false
+	for i, c := range configs {
+		if *c.ID == *config.ID {
+			configs[i] = config
+			replaced = true
+		}
+	}
+	if !replaced {
+		configs = append(configs, config)
+	}
+	n.configs[taskID] = configs
+	return &TaskPushNotificationConfig{TaskID: taskID, PushNotificationConfig: config}, nil
+}
+
+// get returns the config with the given ID, or the first config of the task if the ID is empty.
+func (n *notifier) get(taskID string, configID *string) (*TaskPushNotificationConfig, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, c := range n.configs[taskID] {
+		if configID == nil || *configID == "" || *c.ID == *configID {
+			return &TaskPushNotificationConfig{TaskID: taskID, PushNotificationConfig: c}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: task %s", ErrPushConfigNotFound, taskID)
+}
+
+// list returns all configs of the task.
+func (n *notifier) list(taskID string) []TaskPushNotificationConfig {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	res := make([]TaskPushNotificationConfig, 0, len(n.configs[taskID]))
+	for _, c := range n.configs[taskID] {
+		res = append(res, TaskPushNotificationConfig{TaskID: taskID, PushNotificationConfig: c})
+	}
+	return res
+}
+
+// remove deletes the config with the given ID from the task.
+func (n *notifier) remove(taskID, configID string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	configs := n.configs[taskID]
+	for i, c := range configs {
+		if *c.ID == configID {
+			n.configs[taskID] = append(configs[:i], configs[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s of task %s", ErrPushConfigNotFound, configID, taskID)
+}
+
+// notify queues the task state for every push notification config of the task.
+// It never blocks, since it is called while the task is locked: if the queue is full,
+// the notification is dropped. The configs of a task are removed once it reaches a terminal state.
+func (n *notifier) notify(ctx context.Context, task *Task) {
+	n.mu.Lock()
+	configs := append([]PushNotificationConfig(nil), n.configs[task.ID]...)
+	if terminal(task.Status.State) {
+		delete(n.configs, task.ID)
+	}
+	n.mu.Unlock()
+	for _, c := range configs {
+		if ctx.Err() != nil {
+			return
+		}
+		select {
+		case n.queue <- notification{config: c, task: clone(task)}:
+		default:
+			log.Warn("Notification queue is full, %s is not notified that task %s is %s", c.URL, task.ID, task.Status.State)
+		}
+	}
+}
+
+// deliver POSTs the task to the configured URL with the configured token and credentials.
+func (n *notifier) deliver(ctx context.Context, next notification) error {
+	body, err := json.Marshal(next.task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, next.config.URL, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to create POST request for %s: %w", next.config.URL, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if next.config.Token != nil && *next.config.Token != "" {
+		req.Header.Set("X-A2A-Notification-Token", *next.config.Token)
+	}
+	if auth := next.config.Authentication; auth != nil && auth.Credentials != nil {
+		for _, scheme := range auth.Schemes {
+			if strings.EqualFold(scheme, "bearer") || strings.EqualFold(scheme, "basic") {
+				req.Header.Set("Authorization", fmt.Sprintf("%s %s", canonical(scheme), *auth.Credentials))
+				break
+			}
+		}
+	}
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Warn("Failed to close notification response body: %v", err)
+		}
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	log.Debug("Notified %s that task %s is %s", next.config.URL, next.task.ID, next.task.Status.State)
+	return nil
+}
+
+func validate(config PushNotificationConfig) error {
+	u, err := url.Parse(config.URL)
+	if err != nil {
+		return fmt.Errorf("invalid push notification URL %q: %w", config.URL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("invalid push notification URL %q, an absolute http(s) URL is expected", config.URL)
+	}
+	return nil
+}
+
+func canonical(scheme string) string {
+	if strings.EqualFold(scheme, "bearer") {
+		return "Bearer"
+	}
+	return "Basic"
+}
@@ -0,0 +1,190 @@
+package protocol
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cqfn/refrax/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_PushesTaskStateChanges(t *testing.T) {
+	received := make(chan *http.Request, 16)
+	states := make(chan TaskState, 16)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var task Task
+		if err := json.NewDecoder(r.Body).Decode(&task); err == nil {
+			received <- r
+			states <- task.Status.State
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer hook.Close()
+	serv, port := testServer(t)
+	<-serv.Ready()
+	defer func() { require.NoError(t, serv.Shutdown(), "Failed to close server") }()
+	client := NewClient(fmt.Sprintf("http://localhost:%d", port))
+	blocking := false
+	token := "secret"
+	credentials := "abc"
+
+	_, err := client.SendMessage(context.Background(), NewMessageSendParams().
+		WithMessage(askJoke()).
+		WithConfiguration(&MessageSendConfiguration{
+			Blocking: &blocking,
+			PushNotificationConfig: &PushNotificationConfig{
+				URL:   hook.URL,
+				Token: &token,
+				Authentication: &PushNotificationAuthenticationInfo{
+					Schemes:     []string{"Bearer"},
+					Credentials: &credentials,
+				},
+			},
+		}))
+
+	require.NoError(t, err, "Failed to send message")
+	last := TaskStateUnknown
+	for last != TaskStateCompleted {
+		select {
+		case last = <-states:
+			r := <-received
+			assert.Equal(t, "secret", r.Header.Get("X-A2A-Notification-Token"))
+			assert.Equal(t, "Bearer abc", r.Header.Get("Authorization"))
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "Task completion was not pushed")
+		}
+	}
+}
+
+func TestServer_ManagesPushNotificationConfigs(t *testing.T) {
+	port, err := util.FreePort()
+	require.NoError(t, err)
+	serv := NewServer(&testCard, port)
+	release := make(chan struct{})
+	defer close(release)
+	serv.MsgHandler(func(ctx context.Context, msg *Message) (*Message, error) {
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+		return joke(ctx, msg)
+	})
+	go func() { _ = serv.ListenAndServe() }()
+	<-serv.Ready()
+	defer func() { require.NoError(t, serv.Shutdown(), "Failed to close server") }()
+	client := NewClient(fmt.Sprintf("http://localhost:%d", port))
+	blocking := false
+	resp, err := client.SendMessage(context.Background(), NewMessageSendParams().
+		WithMessage(askJoke().WithTaskID("42")).
+		WithConfiguration(&MessageSendConfiguration{Blocking: &blocking}))
+	require.NoError(t, err, "Failed to send message")
+	require.NotNil(t, resp)
+
+	config, err := client.SetPushNotificationConfig(&TaskPushNotificationConfig{
+		TaskID:                 "42",
+		PushNotificationConfig: PushNotificationConfig{URL: "http://ci.example.com/hook"},
+	})
+
+	require.NoError(t, err, "Failed to set push notification config")
+	require.NotNil(t, config.PushNotificationConfig.ID, "Config should get an ID")
+	list := call(t, port, "tasks/pushNotificationConfig/list", ListTaskPushNotificationConfigParams{ID: "42"})
+	assert.Len(t, list.Result, 1, "Task should have one config")
+	removed := call(t, port, "tasks/pushNotificationConfig/delete", DeleteTaskPushNotificationConfigParams{
+		ID:                       "42",
+		PushNotificationConfigID: *config.PushNotificationConfig.ID,
+	})
+	assert.Nil(t, removed.Error, "Config should be deleted")
+	missing := call(t, port, "tasks/pushNotificationConfig/get", GetTaskPushNotificationConfigParams{ID: "42"})
+	assert.NotNil(t, missing.Error, "Deleted config should not be found")
+}
+
+func TestServer_RejectsConfigOfUnknownTask(t *testing.T) {
+	serv, port := testServer(t)
+	<-serv.Ready()
+	defer func() { require.NoError(t, serv.Shutdown(), "Failed to close server") }()
+	client := NewClient(fmt.Sprintf("http://localhost:%d", port))
+
+	_, err := client.SetPushNotificationConfig(&TaskPushNotificationConfig{
+		TaskID:                 "unknown",
+		PushNotificationConfig: PushNotificationConfig{URL: "http://ci.example.com/hook"},
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "task not found")
+}
+
+func TestServer_RejectsConfigOfFinishedTask(t *testing.T) {
+	serv, port := testServer(t)
+	<-serv.Ready()
+	defer func() { require.NoError(t, serv.Shutdown(), "Failed to close server") }()
+	client := NewClient(fmt.Sprintf("http://localhost:%d", port))
+	_, err := client.SendMessage(context.Background(), NewMessageSendParams().WithMessage(askJoke().WithTaskID("42")))
+	require.NoError(t, err, "Failed to send message")
+
+	_, err = client.SetPushNotificationConfig(&TaskPushNotificationConfig{
+		TaskID:                 "42",
+		PushNotificationConfig: PushNotificationConfig{URL: "http://ci.example.com/hook"},
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "task is finished")
+}
+
+func TestServer_ListsNoConfigsOfUnknownTask(t *testing.T) {
+	serv, port := testServer(t)
+	<-serv.Ready()
+	defer func() { require.NoError(t, serv.Shutdown(), "Failed to close server") }()
+
+	resp := call(t, port, "tasks/pushNotificationConfig/list", ListTaskPushNotificationConfigParams{ID: "unknown"})
+
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, ErrCodeTaskNotFound, resp.Error.Code)
+}
+
+func call(t *testing.T, port int, method string, params any) *JSONRPCResponse {
+	t.Helper()
+	client := NewClient(fmt.Sprintf("http://localhost:%d", port)).(*a2aClient)
+	var resp JSONRPCResponse
+	err := client.doRequest(context.Background(), JSONRPCRequest{JSONRPC: "2.0", ID: "1", Method: method, Params: params}, &resp)
+	require.NoError(t, err, "Failed to call %s", method)
+	return &resp
+}
+
+func TestNotifier_DropsNotificationsWhenQueueIsFull(t *testing.T) {
+	n := newNotifier()
+	n.queue = make(chan notification, 1)
+	_, err := n.set("42", PushNotificationConfig{URL: "http://ci.example.com/hook"})
+	require.NoError(t, err)
+	working := &Task{ID: "42", Status: status(TaskStateWorking, nil)}
+	done := make(chan struct{})
+
+	go func() {
+		n.notify(context.Background(), working)
+		n.notify(context.Background(), working)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "Notify should not block on a full queue")
+	}
+	assert.Len(t, n.queue, 1, "Only the first notification should be queued")
+}
+
+func TestNotifier_RemovesConfigsOfFinishedTask(t *testing.T) {
+	n := newNotifier()
+	_, err := n.set("42", PushNotificationConfig{URL: "http://ci.example.com/hook"})
+	require.NoError(t, err)
+
+	n.notify(context.Background(), &Task{ID: "42", Status: status(TaskStateCompleted, nil)})
+
+	assert.Len(t, n.queue, 1, "Completion should be queued")
+	assert.Empty(t, n.list("42"), "Configs of a finished task should be removed")
+}
@@ -15,6 +15,9 @@ type Server interface {
 	// Handler sets the handler function for processing requests.
 	Handler(handler Handler)
 
+	// TaskStore sets the store that keeps the tasks of the server.
+	TaskStore(store TaskStore)
+
 	// Shutdown stops the server gracefully.
 	Shutdown() error
 
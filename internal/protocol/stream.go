@@ -0,0 +1,166 @@
+package protocol
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/cqfn/refrax/internal/log"
+	"github.com/google/uuid"
+)
+
+// publisherKey is the context key under which the publisher of the current stream is stored.
+type publisherKey struct{}
+
+// publisher sends events to the client that streams the current request.
+type publisher struct {
+	mu        sync.Mutex
+	w         http.ResponseWriter
+	flusher   http.Flusher
+	id        string
+	taskID    string
+	contextID string
+	closed    bool
+}
+
+// Progress reports an intermediate status message of the current task.
+// The message becomes the status of the task and is published to the client that streams the request.
+func Progress(ctx context.Context, text string) {
+	msg := NewMessage().
+		WithRole("agent").
+		WithMessageID(uuid.NewString()).
+		AddPart(NewText(text))
+	if task, ok := ctx.Value(taskKey{}).(*current); ok {
+		msg.WithTaskID(task.id)
+		if _, err := task.tasks.update(task.id, TaskStateWorking, msg); err != nil {
+			log.Warn("Failed to update task %s with progress %q: %v", task.id, text, err)
+		}
+	}
+	pub, ok := ctx.Value(publisherKey{}).(*publisher)
+	if !ok {
+		return
+	}
+	msg.WithTaskID(pub.taskID).WithContextID(pub.contextID)
+	if err := pub.publish(NewStatusUpdate(pub.taskID, pub.contextID, TaskStateWorking, msg, false)); err != nil {
+		log.Warn("Failed to publish progress %q: %v", text, err)
+	}
+}
+
+// PublishArtifact publishes an artifact to the client that streams the current request.
+// It does nothing if the request was not sent with message/stream.
+func PublishArtifact(ctx context.Context, artifact *Artifact) {
+	pub, ok := ctx.Value(publisherKey{}).(*publisher)
+	if !ok {
+		return
+	}
+	if err := pub.publish(NewArtifactUpdate(pub.taskID, pub.contextID, artifact)); err != nil {
+		log.Warn("Failed to publish artifact %s: %v", artifact.ArtifactID, err)
+	}
+}
+
+// publish writes a single Server-Sent Event with the JSON-RPC response that wraps the event.
+func (p *publisher) publish(event any) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return fmt.Errorf("stream of task %s is already closed", p.taskID)
+	}
+	data, err := json.Marshal(success(p.id, event))
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	if _, err = fmt.Fprintf(p.w, "data: %s\n\n", data); err != nil {
+		return fmt.Errorf("failed to write event: %w", err)
+	}
+	p.flusher.Flush()
+	return nil
+}
+
+// close prevents any further events, e.g. from goroutines that outlive the request.
+func (p *publisher) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+}
+
+// stream handles the message/stream request and sends the events as Server-Sent Events.
+// It returns the response that would answer the request if it was not streamed, so that the middleware
+// sees it, and tells whether the stream started. If it didn't, the response still has to be sent.
+func (serv *a2aServer) stream(w http.ResponseWriter, r *http.Request, req *JSONRPCRequest) (*JSONRPCResponse, bool, error) {
+	id := str(req.ID)
+	var params MessageSendParams
+	if err := decode(req.Params, &params); err != nil {
+		resp := failure(id, ErrCodeInvalidParams, err.Error())
+		return &resp, false, nil
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		resp := failure(id, ErrCodeInternalError, "Streaming is not supported by the server")
+		return &resp, false, nil
+	}
+	msg := params.Message
+	if msg == nil {
+		resp := failure(id, ErrCodeInvalidParams, "Message is required")
+		return &resp, false, nil
+	}
+	task, ctx, err := serv.tasks.start(r.Context(), msg)
+	if err != nil {
+		resp := failure(id, ErrCodeInvalidRequest, err.Error())
+		return &resp, false, nil
+	}
+	pub := &publisher{
+		w:         w,
+		flusher:   flusher,
+		id:        id,
+		taskID:    task.ID,
+		contextID: task.ContextID,
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	log.Debug("Streaming the response for message %s, task %s", msg.MessageID, pub.taskID)
+	if err = pub.publish(NewStatusUpdate(pub.taskID, pub.contextID, TaskStateWorking, nil, false)); err != nil {
+		return nil, true, err
+	}
+	defer pub.close()
+	answer, err := serv.execute(context.WithValue(ctx, publisherKey{}, pub), task.ID, msg)
+	if err != nil {
+		state := TaskStateFailed
+		if last, lerr := serv.tasks.get(task.ID, nil); lerr == nil && last.Status.State == TaskStateCanceled {
+			state = TaskStateCanceled
+		}
+		text := fmt.Sprintf("failed to handle message stream: %v", err)
+		resp := failure(id, ErrCodeInternalError, text)
+		return &resp, true, pub.publish(NewStatusUpdate(pub.taskID, pub.contextID, state, reason(text), true))
+	}
+	if answer != nil {
+		answer.WithTaskID(pub.taskID).WithContextID(pub.contextID)
+		if err = pub.publish(answer); err != nil {
+			return nil, true, err
+		}
+	}
+	resp := success(id, answer)
+	return &resp, true, pub.publish(NewStatusUpdate(pub.taskID, pub.contextID, TaskStateCompleted, nil, true))
+}
+
+// decode converts generic JSON-RPC params into the given structure.
+func decode(params, target any) error {
+	pbytes, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to marshal params '%v': %w", params, err)
+	}
+	if err = json.Unmarshal(pbytes, target); err != nil {
+		return fmt.Errorf("failed to unmarshal params '%v': %w", params, err)
+	}
+	return nil
+}
+
+func valueOr(value *string, fallback string) string {
+	if value != nil && *value != "" {
+		return *value
+	}
+	return fallback
+}
@@ -0,0 +1,14 @@
+package protocol
+
+// TaskQueryParams defines the parameters of the tasks/get request.
+type TaskQueryParams struct {
+	ID            string         `json:"id"`                      // Required: task ID
+	HistoryLength *int           `json:"historyLength,omitempty"` // Optional: number of recent messages to return
+	Metadata      map[string]any `json:"metadata,omitempty"`      // Optional: extension metadata
+}
+
+// TaskIDParams defines the parameters of the requests that refer to a task, e.g. tasks/cancel.
+type TaskIDParams struct {
+	ID       string         `json:"id"`                 // Required: task ID
+	Metadata map[string]any `json:"metadata,omitempty"` // Optional: extension metadata
+}
@@ -0,0 +1,61 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrTaskNotFound is returned by a TaskStore when there is no task with the requested ID.
+var ErrTaskNotFound = errors.New("task not found")
+
+// TaskStore keeps the tasks created by the server.
+// Implementations must be safe for concurrent use.
+type TaskStore interface {
+	// Save creates or replaces the task.
+	Save(task *Task) error
+
+	// Load returns the task with the given ID or ErrTaskNotFound.
+	Load(id string) (*Task, error)
+}
+
+// inMemoryTaskStore keeps tasks in memory, they are lost when the server stops.
+type inMemoryTaskStore struct {
+	mu    sync.RWMutex
+	tasks map[string]Task
+}
+
+// NewInMemoryTaskStore creates an empty task store that keeps tasks in memory.
+func NewInMemoryTaskStore() TaskStore {
+	return &inMemoryTaskStore{tasks: make(map[string]Task)}
+}
+
+// Save stores a copy of the task, so later changes of the task do not affect the store.
+func (s *inMemoryTaskStore) Save(task *Task) error {
+	if task.ID == "" {
+		return fmt.Errorf("task without ID can't be saved")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tasks[task.ID] = clone(task)
+	return nil
+}
+
+// Load returns a copy of the stored task.
+func (s *inMemoryTaskStore) Load(id string) (*Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	task, ok := s.tasks[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTaskNotFound, id)
+	}
+	res := clone(&task)
+	return &res, nil
+}
+
+func clone(task *Task) Task {
+	res := *task
+	res.History = append([]Message(nil), task.History...)
+	res.Artifacts = append([]Artifact(nil), task.Artifacts...)
+	return res
+}
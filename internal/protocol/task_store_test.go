@@ -0,0 +1,28 @@
+package protocol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryTaskStore_LoadsSavedTask(t *testing.T) {
+	store := NewInMemoryTaskStore()
+	task := &Task{ID: "1", Kind: KindTask, Status: TaskStatus{State: TaskStateWorking}}
+
+	require.NoError(t, store.Save(task))
+	task.Status.State = TaskStateFailed
+	loaded, err := store.Load("1")
+
+	require.NoError(t, err)
+	assert.Equal(t, TaskStateWorking, loaded.Status.State, "Store should keep a copy of the task")
+}
+
+func TestInMemoryTaskStore_FailsOnUnknownTask(t *testing.T) {
+	store := NewInMemoryTaskStore()
+
+	_, err := store.Load("unknown")
+
+	require.ErrorIs(t, err, ErrTaskNotFound)
+}
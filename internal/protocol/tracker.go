@@ -0,0 +1,168 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cqfn/refrax/internal/log"
+	"github.com/google/uuid"
+)
+
+// errNotCancelable is returned when a task has already reached a terminal state.
+var errNotCancelable = errors.New("task can't be canceled")
+
+// errTaskFinished is returned when a task that has already reached a terminal state can't take a request.
+var errTaskFinished = errors.New("task is finished")
+
+// taskKey is the context key under which the task of the current request is stored.
+type taskKey struct{}
+
+// current refers to the task that is handled within a context.
+type current struct {
+	tasks *tracker
+	id    string
+}
+
+// tracker follows the lifecycle of the server tasks and cancels the running ones on request.
+type tracker struct {
+	mu      sync.Mutex
+	store   TaskStore
+	running map[string]context.CancelFunc
+	changed func(task *Task)
+}
+
+// newTracker creates a tracker that keeps tasks in the store and reports every state change.
+// The changes are reported in order while the tracker is locked, so the callback must not block.
+func newTracker(store TaskStore, changed func(task *Task)) *tracker {
+	return &tracker{
+		store:   store,
+		running: make(map[string]context.CancelFunc),
+		changed: changed,
+	}
+}
+
+// start registers a submitted task for the message and returns the context the task runs in.
+// The context is canceled when the task is canceled or finished.
+func (t *tracker) start(ctx context.Context, msg *Message) (*Task, context.Context, error) {
+	task := &Task{
+		ID:        valueOr(msg.TaskID, uuid.NewString()),
+		ContextID: valueOr(msg.ContextID, uuid.NewString()),
+		Kind:      KindTask,
+		Status:    status(TaskStateSubmitted, nil),
+		History:   []Message{*msg},
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.running[task.ID]; ok {
+		return nil, nil, fmt.Errorf("task %s is already running", task.ID)
+	}
+	if err := t.store.Save(task); err != nil {
+		return nil, nil, fmt.Errorf("failed to save task %s: %w", task.ID, err)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	t.running[task.ID] = cancel
+	return task, context.WithValue(ctx, taskKey{}, &current{tasks: t, id: task.ID}), nil
+}
+
+// update moves the task to the new state with an optional status message.
+// Tasks that have already reached a terminal state are left untouched.
+func (t *tracker) update(id string, state TaskState, msg *Message) (*Task, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.change(id, state, msg, false)
+}
+
+// complete marks the task as completed and appends the answer to its history.
+func (t *tracker) complete(id string, answer *Message) (*Task, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.change(id, TaskStateCompleted, answer, true)
+}
+
+// cancel stops the running task and marks it as canceled.
+func (t *tracker) cancel(id string) (*Task, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	task, err := t.store.Load(id)
+	if err != nil {
+		return nil, err
+	}
+	if terminal(task.Status.State) {
+		return nil, fmt.Errorf("%w: task %s is already %s", errNotCancelable, id, task.Status.State)
+	}
+	log.Debug("Canceling task %s", id)
+	return t.change(id, TaskStateCanceled, nil, false)
+}
+
+// active runs the function while the tracker is locked, so that the task can't finish meanwhile.
+// It fails if there is no such task or the task has already reached a terminal state.
+func (t *tracker) active(id string, fn func() error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	task, err := t.store.Load(id)
+	if err != nil {
+		return err
+	}
+	if terminal(task.Status.State) {
+		return fmt.Errorf("%w: task %s is already %s", errTaskFinished, id, task.Status.State)
+	}
+	return fn()
+}
+
+// get returns the task with at most the given number of recent messages in its history.
+func (t *tracker) get(id string, history *int) (*Task, error) {
+	task, err := t.store.Load(id)
+	if err != nil {
+		return nil, err
+	}
+	if history != nil && *history >= 0 && len(task.History) > *history {
+		task.History = task.History[len(task.History)-*history:]
+	}
+	return task, nil
+}
+
+func (t *tracker) change(id string, state TaskState, msg *Message, remember bool) (*Task, error) {
+	task, err := t.store.Load(id)
+	if err != nil {
+		return nil, err
+	}
+	if terminal(task.Status.State) {
+		return task, nil
+	}
+	task.Status = status(state, msg)
+	if remember && msg != nil {
+		task.History = append(task.History, *msg)
+	}
+	if err = t.store.Save(task); err != nil {
+		return nil, fmt.Errorf("failed to save task %s: %w", id, err)
+	}
+	t.changed(task)
+	if terminal(state) {
+		if cancel, ok := t.running[id]; ok {
+			cancel()
+			delete(t.running, id)
+		}
+	}
+	return task, nil
+}
+
+func status(state TaskState, msg *Message) TaskStatus {
+	now := time.Now().UTC().Format(time.RFC3339)
+	return TaskStatus{
+		State:     state,
+		Message:   msg,
+		Timestamp: &now,
+	}
+}
+
+func terminal(state TaskState) bool {
+	switch state {
+	case TaskStateCompleted, TaskStateCanceled, TaskStateFailed, TaskStateRejected:
+		return true
+	default:
+		return false
+	}
+}
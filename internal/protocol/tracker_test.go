@@ -0,0 +1,53 @@
+package protocol
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker_CancelReachesContext(t *testing.T) {
+	tasks := newTracker(NewInMemoryTaskStore(), func(*Task) {})
+	task, ctx, err := tasks.start(context.Background(), askJoke())
+	require.NoError(t, err)
+
+	canceled, err := tasks.cancel(task.ID)
+
+	require.NoError(t, err)
+	assert.Equal(t, TaskStateCanceled, canceled.Status.State)
+	assert.ErrorIs(t, ctx.Err(), context.Canceled, "Task context should be canceled")
+}
+
+func TestTracker_KeepsTerminalState(t *testing.T) {
+	tasks := newTracker(NewInMemoryTaskStore(), func(*Task) {})
+	task, _, err := tasks.start(context.Background(), askJoke())
+	require.NoError(t, err)
+	_, err = tasks.cancel(task.ID)
+	require.NoError(t, err)
+
+	_, err = tasks.complete(task.ID, tellJoke())
+	require.NoError(t, err)
+	last, err := tasks.get(task.ID, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, TaskStateCanceled, last.Status.State, "Canceled task should stay canceled")
+	_, err = tasks.cancel(task.ID)
+	assert.ErrorIs(t, err, errNotCancelable)
+}
+
+func TestTracker_TrimsHistory(t *testing.T) {
+	tasks := newTracker(NewInMemoryTaskStore(), func(*Task) {})
+	task, _, err := tasks.start(context.Background(), askJoke())
+	require.NoError(t, err)
+	_, err = tasks.complete(task.ID, tellJoke())
+	require.NoError(t, err)
+	one := 1
+
+	last, err := tasks.get(task.ID, &one)
+
+	require.NoError(t, err)
+	require.Len(t, last.History, 1)
+	assert.Equal(t, tellJoke().MessageID, last.History[0].MessageID)
+}
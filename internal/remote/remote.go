@@ -0,0 +1,148 @@
+// Package remote provides clients for agents that are already running somewhere else and
+// are reachable over the A2A protocol.
+package remote
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cqfn/refrax/internal/critic"
+	"github.com/cqfn/refrax/internal/domain"
+	"github.com/cqfn/refrax/internal/facilitator"
+	"github.com/cqfn/refrax/internal/fixer"
+	"github.com/cqfn/refrax/internal/log"
+	"github.com/cqfn/refrax/internal/protocol"
+	"github.com/cqfn/refrax/internal/reviewer"
+)
+
+// agent is a generic A2A-backed agent that sends jobs to a remote server.
+type agent struct {
+	url    string
+	name   string
+	client protocol.Client
+}
+
+// connect creates a generic remote agent and validates its agent card.
+// The card must be reachable and must declare the skill the caller expects.
+func connect(name, url, skill string) (*agent, error) {
+	client := protocol.NewClient(url)
+	card, err := client.AgentCard()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent card of remote %s at %s: %w", name, url, err)
+	}
+	if card.Name == "" {
+		return nil, fmt.Errorf("agent card of remote %s at %s has no name", name, url)
+	}
+	if !card.HasSkill(skill) {
+		return nil, fmt.Errorf("remote agent %q at %s is not a %s, it does not declare the %q skill", card.Name, url, name, skill)
+	}
+	log.Info("Connected to remote %s %q (version %s) at %s", name, card.Name, card.Version, url)
+	return &agent{
+		url:    url,
+		name:   name,
+		client: client,
+	}, nil
+}
+
+// send marshals the job, sends it to the remote agent and unmarshals the answer.
+func (a *agent) send(ctx context.Context, job *domain.Job) (*domain.Artifacts, error) {
+	log.Debug("Asking remote %s (%s)...", a.name, a.url)
+	resp, err := a.client.SendMessage(ctx, job.Marshal())
+	if err != nil {
+		return nil, fmt.Errorf("failed to send message to remote %s at %s: %w", a.name, a.url, err)
+	}
+	msg, ok := resp.Result.(*protocol.Message)
+	if !ok {
+		return nil, fmt.Errorf("remote %s at %s returned unexpected result %T", a.name, a.url, resp.Result)
+	}
+	return domain.UnmarshalArtifacts(msg)
+}
+
+// stream sends the job with message/stream, logs the progress and returns the final answer.
+func (a *agent) stream(job *domain.Job) (*domain.Artifacts, error) {
+	log.Debug("Streaming a job to remote %s (%s)...", a.name, a.url)
+	answer, err := protocol.Await(a.client.StreamMessage(job.Marshal()), func(text string) {
+		log.Info("Remote %s: %s", a.name, text)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to stream message to remote %s at %s: %w", a.name, a.url, err)
+	}
+	return domain.UnmarshalArtifacts(answer)
+}
+
+// Critic is a domain.Critic that delegates reviews to a remote critic agent.
+type Critic struct {
+	origin *agent
+}
+
+// NewCritic creates a client for the critic agent running at the given URL.
+func NewCritic(url string) (*Critic, error) {
+	origin, err := connect("critic", url, critic.Skill)
+	if err != nil {
+		return nil, err
+	}
+	return &Critic{origin: origin}, nil
+}
+
+// Review sends the class to the remote critic and returns its suggestions.
+func (c *Critic) Review(ctx context.Context, job *domain.Job) (*domain.Artifacts, error) {
+	return c.origin.send(ctx, job)
+}
+
+// Fixer is a domain.Fixer that delegates fixes to a remote fixer agent.
+type Fixer struct {
+	origin *agent
+}
+
+// NewFixer creates a client for the fixer agent running at the given URL.
+func NewFixer(url string) (*Fixer, error) {
+	origin, err := connect("fixer", url, fixer.Skill)
+	if err != nil {
+		return nil, err
+	}
+	return &Fixer{origin: origin}, nil
+}
+
+// Fix sends the class and suggestions to the remote fixer and returns the fixed class.
+func (f *Fixer) Fix(ctx context.Context, job *domain.Job) (*domain.Artifacts, error) {
+	return f.origin.send(ctx, job)
+}
+
+// Reviewer is a domain.Reviewer that delegates reviews to a remote reviewer agent.
+type Reviewer struct {
+	origin *agent
+}
+
+// NewReviewer creates a client for the reviewer agent running at the given URL.
+func NewReviewer(url string) (*Reviewer, error) {
+	origin, err := connect("reviewer", url, reviewer.Skill)
+	if err != nil {
+		return nil, err
+	}
+	return &Reviewer{origin: origin}, nil
+}
+
+// Review asks the remote reviewer to check the project and returns its suggestions.
+func (r *Reviewer) Review(ctx context.Context, job *domain.Job) (*domain.Artifacts, error) {
+	return r.origin.send(ctx, job)
+}
+
+// Facilitator is a domain.Facilitator that delegates refactoring to a remote facilitator agent.
+type Facilitator struct {
+	origin *agent
+}
+
+// NewFacilitator creates a client for the facilitator agent running at the given URL.
+func NewFacilitator(url string) (*Facilitator, error) {
+	origin, err := connect("facilitator", url, facilitator.Skill)
+	if err != nil {
+		return nil, err
+	}
+	return &Facilitator{origin: origin}, nil
+}
+
+// Refactor streams the project classes to the remote facilitator and returns the refactored classes.
+// The progress reported by the facilitator is logged while the refactoring goes on.
+func (f *Facilitator) Refactor(job *domain.Job) (*domain.Artifacts, error) {
+	return f.origin.stream(job)
+}
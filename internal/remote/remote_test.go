@@ -0,0 +1,59 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cqfn/refrax/internal/brain"
+	"github.com/cqfn/refrax/internal/critic"
+	"github.com/cqfn/refrax/internal/domain"
+	"github.com/cqfn/refrax/internal/fixer"
+	"github.com/cqfn/refrax/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCritic_ReviewsRemotely(t *testing.T) {
+	port, err := util.FreePort()
+	require.NoError(t, err)
+	server := critic.NewCritic(brain.NewMock(), port, true)
+	go func() { _ = server.ListenAndServe() }()
+	defer func() { require.NoError(t, server.Shutdown()) }()
+	<-server.Ready()
+	job := domain.Job{
+		Descr:   &domain.Description{Text: "refactor the class"},
+		Classes: []domain.Class{domain.NewInMemoryClass("Foo", "Foo.java", "class Foo {}")},
+	}
+	ctc, err := NewCritic(fmt.Sprintf("http://localhost:%d", port))
+	require.NoError(t, err)
+
+	artifacts, err := ctc.Review(context.Background(), &job)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Critique for class Foo", artifacts.Descr.Text)
+}
+
+func TestNewCritic_FailsWhenAgentIsUnreachable(t *testing.T) {
+	port, err := util.FreePort()
+	require.NoError(t, err)
+
+	_, err = NewCritic(fmt.Sprintf("http://localhost:%d", port))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get agent card of remote critic")
+}
+
+func TestNewCritic_RejectsAgentWithAnotherSkill(t *testing.T) {
+	port, err := util.FreePort()
+	require.NoError(t, err)
+	server := fixer.NewFixer(brain.NewMock(), port, true)
+	go func() { _ = server.ListenAndServe() }()
+	defer func() { require.NoError(t, server.Shutdown()) }()
+	<-server.Ready()
+
+	_, err = NewCritic(fmt.Sprintf("http://localhost:%d", port))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not a critic")
+}
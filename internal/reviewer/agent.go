@@ -2,6 +2,7 @@ package reviewer
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -28,11 +29,12 @@ type promptData struct {
 	Stdout  string
 }
 
-func (a *agent) Review() (*domain.Artifacts, error) {
+// Review runs the check commands in the directory, or in the current one if the directory is empty.
+func (a *agent) Review(ctx context.Context, dir string) (*domain.Artifacts, error) {
 	var res []domain.Suggestion
 	a.logger.Info("Starting review using %d commands, %s", len(a.cmds), strings.Join(a.cmds, ", "))
 	for _, cmd := range a.cmds {
-		suggestions, err := a.runCmd(cmd)
+		suggestions, err := a.runCmd(ctx, cmd, dir)
 		if err != nil {
 			return nil, fmt.Errorf("failed to run command %s: %w", cmd, err)
 		}
@@ -45,20 +47,24 @@ func (a *agent) Review() (*domain.Artifacts, error) {
 	return artifacts, nil
 }
 
-func (a *agent) runCmd(cmd string) ([]domain.Suggestion, error) {
+func (a *agent) runCmd(ctx context.Context, cmd, dir string) ([]domain.Suggestion, error) {
 	var out bytes.Buffer
 	var errOut bytes.Buffer
-	root, err := os.Getwd()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get current working directory: %w", err)
+	root := dir
+	if root == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current working directory: %w", err)
+		}
+		root = wd
 	}
 	parts := strings.Split(cmd, " ")
-	command := exec.Command(parts[0], parts[1:]...) // #nosec G204
+	command := exec.CommandContext(ctx, parts[0], parts[1:]...) // #nosec G204
 	command.Stdout = &out
 	command.Stderr = &errOut
 	command.Dir = root
 	a.logger.Info("Running review command: %s in %s", cmd, root)
-	err = command.Run()
+	err := command.Run()
 	if err == nil {
 		a.logger.Info("Review command completed successfully: %s", cmd)
 		return make([]domain.Suggestion, 0), nil
@@ -78,7 +84,7 @@ func (a *agent) runCmd(cmd string) ([]domain.Suggestion, error) {
 		Data: data,
 		Name: "reviewer/review.md.tmpl",
 	}
-	raw, err := a.ai.Ask(prompt.String())
+	raw, err := a.ai.Ask(ctx, prompt.String())
 	if err != nil {
 		return nil, fmt.Errorf("failed to ask AI for suggestions: %w", err)
 	}
@@ -11,12 +11,15 @@ import (
 	"github.com/cqfn/refrax/internal/protocol"
 )
 
+// Skill is the ID of the skill the reviewer agent declares in its agent card.
+const Skill = "review-changes"
+
 // A2AReviewer represents a reviewer agent responsible for reviewing changes.
 type A2AReviewer struct {
 	server   protocol.Server
 	log      log.Logger
 	port     int
-	original domain.Reviewer
+	original *agent
 }
 
 // NewReviewer creates a new instance of A2AReviewer.
@@ -39,11 +42,9 @@ func NewReviewer(ai brain.Brain, port int, colorless bool, cmds ...string) *A2AR
 }
 
 // Review sends a request for review and returns suggestions.
-func (r *A2AReviewer) Review() (*domain.Artifacts, error) {
+func (r *A2AReviewer) Review(ctx context.Context, job *domain.Job) (*domain.Artifacts, error) {
 	client := protocol.NewClient(fmt.Sprintf("http://localhost:%d", r.port))
-	resp, err := client.SendMessage(
-		protocol.NewMessageSendParams().WithMessage(protocol.NewMessage().AddPart(protocol.NewText("review"))),
-	)
+	resp, err := client.SendMessage(ctx, job.Marshal())
 	if err != nil {
 		return nil, fmt.Errorf("failed to send review request: %w", err)
 	}
@@ -85,12 +86,16 @@ func (r *A2AReviewer) think(ctx context.Context, m *protocol.Message) (*protocol
 	case <-ctx.Done():
 		return nil, fmt.Errorf("context canceled: %w", ctx.Err())
 	default:
-		return r.thinkLong(m)
+		return r.thinkLong(ctx, m)
 	}
 }
 
-func (r *A2AReviewer) thinkLong(_ *protocol.Message) (*protocol.Message, error) {
-	artifacts, err := r.original.Review()
+func (r *A2AReviewer) thinkLong(ctx context.Context, m *protocol.Message) (*protocol.Message, error) {
+	job, err := domain.UnmarshalJob(m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal review job: %w", err)
+	}
+	artifacts, err := r.original.Review(ctx, job.Dir())
 	if err != nil {
 		return nil, fmt.Errorf("failed to review task: %w", err)
 	}
@@ -113,5 +118,5 @@ func agentCard(port int) *protocol.AgentCard {
 		WithDescription("An agent that checks whether the project is stable and changes made haven't break anything").
 		WithURL(fmt.Sprintf("http://localhost:%d", port)).
 		WithVersion("0.0.1").
-		AddSkill("review-changes", "Review Changes", "Review changes made to be sure they don't break the project")
+		AddSkill(Skill, "Review Changes", "Review changes made to be sure they don't break the project")
 }
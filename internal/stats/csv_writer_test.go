@@ -32,7 +32,7 @@ func TestCSVWriter_Print_Success(t *testing.T) {
 	defer func() { _ = file.Close() }()
 	lines, err := csv.NewReader(file).ReadAll()
 	require.NoError(t, err)
-	require.Len(t, lines, 27)
+	require.Len(t, lines, 29)
 	assert.Equal(t, []string{"metric", "test-stats"}, lines[0])
 	assert.Equal(t, []string{"Total LLM messages asked", "3"}, lines[1])
 	assert.Equal(t, []string{"Total LLM request duration", "6s"}, lines[2])
@@ -56,7 +56,7 @@ func TestCSVWriter_PrintSeveral_Success(t *testing.T) {
 	defer func() { _ = file.Close() }()
 	lines, err := csv.NewReader(file).ReadAll()
 	require.NoError(t, err)
-	require.Len(t, lines, 27)
+	require.Len(t, lines, 29)
 	assert.Equal(t, []string{"metric", "first-stats", "second-stats"}, lines[0])
 	assert.Equal(t, []string{"Total LLM messages asked", "1", "1"}, lines[1])
 	assert.Equal(t, []string{"Total LLM request duration", "3s", "3s"}, lines[2])
@@ -2,6 +2,8 @@ package stats
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -14,6 +16,9 @@ type Stats struct {
 	// name is a string that represents the name of the statistics.
 	Name string
 
+	// Model is the AI provider and model that produced the statistics, e.g. "openai/gpt-4o".
+	Model string
+
 	// mu is a mutex to protect concurrent write access to stats.
 	mu sync.Mutex
 
@@ -32,6 +37,9 @@ type Stats struct {
 	// llmrespbytes is an int that stores the number of bytes received in LLM responses.
 	llmrespbytes int
 
+	// llmretries is an int that stores the number of LLM requests that were retried after a failure.
+	llmretries int
+
 	// a2areqs is a slice of time.Duration that stores the duration of each request made to the A2A service.
 	a2areqs []time.Duration
 
@@ -287,6 +295,20 @@ func (s *Stats) LLMReq(duration time.Duration, reqt, respt, reqb, respb int) {
 	s.llmrespbytes += respb
 }
 
+// LLMRetry records a retry of a failed LLM request.
+func (s *Stats) LLMRetry() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.llmretries++
+}
+
+// TotalLLMRetries returns the number of retried LLM requests.
+func (s *Stats) TotalLLMRetries() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.llmretries
+}
+
 // A2AReq records a request statistics made to the A2A service.
 func (s *Stats) A2AReq(duration time.Duration, reqt, respt, reqb, respb int) {
 	s.mu.Lock()
@@ -307,11 +329,13 @@ func (s *Stats) Add(other *Stats) *Stats {
 	defer other.mu.Unlock()
 	combined := &Stats{
 		Name:          fmt.Sprintf("%s + %s", s.Name, other.Name),
+		Model:         models(s.Model, other.Model),
 		llmreq:        append([]time.Duration{}, s.llmreq...),
 		llmreqtokens:  s.llmreqtokens,
 		llmresptokens: s.llmresptokens,
 		llmreqbytes:   s.llmreqbytes,
 		llmrespbytes:  s.llmrespbytes,
+		llmretries:    s.llmretries,
 		a2areqs:       append([]time.Duration{}, s.a2areqs...),
 		a2areqtokens:  s.a2areqtokens,
 		a2aresptokens: s.a2aresptokens,
@@ -326,6 +350,7 @@ func (s *Stats) Add(other *Stats) *Stats {
 	combined.llmresptokens += other.llmresptokens
 	combined.llmreqbytes += other.llmreqbytes
 	combined.llmrespbytes += other.llmrespbytes
+	combined.llmretries += other.llmretries
 	combined.a2areqtokens += other.a2areqtokens
 	combined.a2aresptokens += other.a2aresptokens
 	combined.a2areqbytes += other.a2areqbytes
@@ -381,5 +406,25 @@ func (s *Stats) Entries() []Entry {
 		{"Average A2A response tokens", fmt.Sprintf("%.4f", s.AverageA2ARespTokens())},
 		{"Average A2A request bytes", fmt.Sprintf("%.4f", s.AverageA2AReqBytes())},
 		{"Average A2A response bytes", fmt.Sprintf("%.4f", s.AverageA2ARespBytes())},
+		{"Total LLM retries", fmt.Sprintf("%d", s.TotalLLMRetries())},
+		{"LLM model", valueOr(s.Model, "none")},
+	}
+}
+
+// models combines two comma-separated lists of models without duplicates.
+func models(first, second string) string {
+	res := make([]string, 0)
+	for _, m := range append(strings.Split(first, ", "), strings.Split(second, ", ")...) {
+		if m != "" && !slices.Contains(res, m) {
+			res = append(res, m)
+		}
+	}
+	return strings.Join(res, ", ")
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
 	}
+	return value
 }
@@ -140,3 +140,21 @@ func TestStats_Add_BothEmpty(t *testing.T) {
 	assert.Equal(t, 0, combined.a2areqbytes)
 	assert.Equal(t, 0, combined.a2arespbytes)
 }
+
+func TestStats_Add_CombinesModels(t *testing.T) {
+	critic := &Stats{Name: "critic", Model: "ollama/qwen2.5-coder"}
+	fixer := &Stats{Name: "fixer", Model: "openai/gpt-4o"}
+	facilitator := &Stats{Name: "facilitator", Model: "openai/gpt-4o"}
+
+	combined := (&Stats{}).Add(critic).Add(fixer).Add(facilitator)
+
+	assert.Equal(t, "ollama/qwen2.5-coder, openai/gpt-4o", combined.Model)
+}
+
+func TestStats_Entries_ReportModel(t *testing.T) {
+	s := &Stats{Name: "critic", Model: "ollama/qwen2.5-coder"}
+
+	entries := s.Entries()
+
+	assert.Equal(t, Entry{"LLM model", "ollama/qwen2.5-coder"}, entries[len(entries)-1])
+}
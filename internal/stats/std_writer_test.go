@@ -42,7 +42,7 @@ func TestStdWriterPrintHandlesEmptyStats(t *testing.T) {
 
 	require.NoError(t, err)
 	entries := m.Messages
-	require.Len(t, entries, 26)
+	require.Len(t, entries, 28)
 	assert.Equal(t, "mock info: Total LLM messages asked: 0", entries[0])
 }
 
@@ -1,6 +1,24 @@
 package tool
 
+import "fmt"
+
 // Tool defines the interface for a tool that can be used to identify and report imperfections in artifacts.
 type Tool interface {
 	Imperfections() string
 }
+
+// New creates the tools with the given names. The "none" name stands for no tools at all.
+// Aibolit is not accepted yet, since it can't check the classes the critic reviews.
+func New(names ...string) ([]Tool, error) {
+	res := make([]Tool, 0, len(names))
+	for _, name := range names {
+		switch name {
+		case "none":
+		case "aibolit":
+			return nil, fmt.Errorf("tool %q is not supported yet, it can't check the classes of the project", name)
+		default:
+			return nil, fmt.Errorf("unknown tool %q, expected: none", name)
+		}
+	}
+	return res, nil
+}
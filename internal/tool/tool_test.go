@@ -0,0 +1,28 @@
+package tool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_RefusesAibolitUntilItChecksProjectClasses(t *testing.T) {
+	_, err := New("aibolit")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not supported yet")
+}
+
+func TestNew_CreatesNothingForNone(t *testing.T) {
+	tools, err := New("none")
+
+	require.NoError(t, err)
+	assert.Empty(t, tools)
+}
+
+func TestNew_FailsOnUnknownTool(t *testing.T) {
+	_, err := New("qulice")
+
+	require.Error(t, err)
+}